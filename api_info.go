@@ -0,0 +1,83 @@
+package sssg
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+// APIInfo describes where an API lives and which versions the NAS supports
+type APIInfo struct {
+	Path          string `json:"path"`
+	MinVersion    int    `json:"minVersion"`
+	MaxVersion    int    `json:"maxVersion"`
+	RequestFormat string `json:"requestFormat,omitempty"`
+}
+
+// QueryAPIInfo queries SYNO.API.Info for every API the NAS exposes and
+// refreshes the cache used to pick endpoints and versions
+func (c *SurveillanceStationClient) QueryAPIInfo() (map[string]APIInfo, error) {
+	return c.QueryAPIInfoContext(context.Background())
+}
+
+// QueryAPIInfoContext queries SYNO.API.Info, bound to ctx
+func (c *SurveillanceStationClient) QueryAPIInfoContext(ctx context.Context) (map[string]APIInfo, error) {
+	c.infoMu.Lock()
+	defer c.infoMu.Unlock()
+
+	return c.queryAPIInfo(ctx)
+}
+
+// queryAPIInfo fetches and caches the API info, the caller must hold infoMu
+func (c *SurveillanceStationClient) queryAPIInfo(ctx context.Context) (map[string]APIInfo, error) {
+	endpoint := fmt.Sprintf("%s/webapi/query.cgi", c.BaseURL)
+	params := url.Values{}
+	params.Set("api", "SYNO.API.Info")
+	params.Set("method", "Query")
+	params.Set("version", "1")
+	params.Set("query", "ALL")
+
+	var data map[string]APIInfo
+	if err := c.do(ctx, endpoint, "SYNO.API.Info", "Query", params, &data); err != nil {
+		return nil, fmt.Errorf("failed to query API info: %w", err)
+	}
+
+	c.apiInfo = data
+	return data, nil
+}
+
+// cachedAPIInfo returns the cached API info, querying it on first use
+func (c *SurveillanceStationClient) cachedAPIInfo(ctx context.Context) (map[string]APIInfo, error) {
+	c.infoMu.Lock()
+	defer c.infoMu.Unlock()
+
+	if c.apiInfo != nil {
+		return c.apiInfo, nil
+	}
+	return c.queryAPIInfo(ctx)
+}
+
+// resolveAPI returns the endpoint of api and the highest version supported
+// by both the NAS and this client, which implements minVersion to maxVersion
+func (c *SurveillanceStationClient) resolveAPI(ctx context.Context, api string, minVersion, maxVersion int) (string, int, error) {
+	infos, err := c.cachedAPIInfo(ctx)
+	if err != nil {
+		return "", 0, err
+	}
+
+	info, ok := infos[api]
+	if !ok {
+		return "", 0, fmt.Errorf("%w: %s is not available on the NAS", ErrAPIUnsupported, api)
+	}
+
+	version := maxVersion
+	if info.MaxVersion < version {
+		version = info.MaxVersion
+	}
+	if version < minVersion || version < info.MinVersion {
+		return "", 0, fmt.Errorf("%w: %s versions %d-%d on the NAS, client requires %d-%d",
+			ErrAPIUnsupported, api, info.MinVersion, info.MaxVersion, minVersion, maxVersion)
+	}
+
+	return fmt.Sprintf("%s/webapi/%s", c.BaseURL, info.Path), version, nil
+}
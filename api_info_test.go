@@ -0,0 +1,70 @@
+package sssg
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestResolveAPIVersionNegotiation(t *testing.T) {
+	var queries int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries++
+		w.Write([]byte(`{"success":true,"data":{
+			"SYNO.SurveillanceStation.Camera":{"path":"entry.cgi","minVersion":1,"maxVersion":7},
+			"SYNO.SurveillanceStation.HomeMode":{"path":"entry.cgi","minVersion":2,"maxVersion":3}
+		}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "user", "pass", false)
+	ctx := context.Background()
+
+	endpoint, version, err := client.resolveAPI(ctx, "SYNO.SurveillanceStation.Camera", 1, 9)
+	if err != nil {
+		t.Fatalf("Failed to resolve Camera API: %v", err)
+	}
+	if version != 7 || endpoint != server.URL+"/webapi/entry.cgi" {
+		t.Errorf("Unexpected resolution: %s version %d", endpoint, version)
+	}
+
+	if _, _, err := client.resolveAPI(ctx, "SYNO.SurveillanceStation.HomeMode", 1, 1); !errors.Is(err, ErrAPIUnsupported) {
+		t.Errorf("Expected ErrAPIUnsupported for version mismatch, got: %v", err)
+	}
+	if _, _, err := client.resolveAPI(ctx, "SYNO.SurveillanceStation.PTZ", 1, 5); !errors.Is(err, ErrAPIUnsupported) {
+		t.Errorf("Expected ErrAPIUnsupported for missing API, got: %v", err)
+	}
+
+	if queries != 1 {
+		t.Errorf("Expected API info to be queried once, got %d", queries)
+	}
+}
+
+func TestLoginUsesAPIAuthWhenAvailable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/query.cgi"):
+			w.Write([]byte(`{"success":true,"data":{"SYNO.API.Auth":{"path":"auth.cgi","minVersion":1,"maxVersion":7}}}`))
+		case strings.HasSuffix(r.URL.Path, "/auth.cgi"):
+			query := r.URL.Query()
+			if query.Get("version") != "6" || query.Get("session") != "SurveillanceStation" {
+				t.Errorf("Unexpected auth query: %s", r.URL.RawQuery)
+			}
+			w.Write([]byte(`{"success":true,"data":{"sid":"api-auth"}}`))
+		default:
+			t.Errorf("Unexpected request: %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "user", "pass", false)
+	if err := client.Login(); err != nil {
+		t.Fatalf("Login failed: %v", err)
+	}
+	if client.Session != "api-auth" {
+		t.Errorf("Unexpected session: %s", client.Session)
+	}
+}
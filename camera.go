@@ -0,0 +1,317 @@
+package sssg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// CameraStatus is the connection status of a camera as reported by Camera.List
+type CameraStatus int
+
+const (
+	CameraStatusNormal                 CameraStatus = 1
+	CameraStatusDeleted                CameraStatus = 2
+	CameraStatusDisconnected           CameraStatus = 3
+	CameraStatusUnavailable            CameraStatus = 4
+	CameraStatusReady                  CameraStatus = 5
+	CameraStatusInaccessible           CameraStatus = 6
+	CameraStatusDisabled               CameraStatus = 7
+	CameraStatusUnrecognized           CameraStatus = 8
+	CameraStatusSetting                CameraStatus = 9
+	CameraStatusServerDisconnected     CameraStatus = 10
+	CameraStatusMigrating              CameraStatus = 11
+	CameraStatusOthers                 CameraStatus = 12
+	CameraStatusStorageRemoved         CameraStatus = 13
+	CameraStatusStopping               CameraStatus = 14
+	CameraStatusConnectionIssueHistory CameraStatus = 15
+	CameraStatusUnauthorized           CameraStatus = 16
+	CameraStatusRTSPError              CameraStatus = 17
+	CameraStatusNoVideo                CameraStatus = 18
+)
+
+var cameraStatusNames = map[CameraStatus]string{
+	CameraStatusNormal:                 "Normal",
+	CameraStatusDeleted:                "Deleted",
+	CameraStatusDisconnected:           "Disconnected",
+	CameraStatusUnavailable:            "Unavailable",
+	CameraStatusReady:                  "Ready",
+	CameraStatusInaccessible:           "Inaccessible",
+	CameraStatusDisabled:               "Disabled",
+	CameraStatusUnrecognized:           "Unrecognized",
+	CameraStatusSetting:                "Setting",
+	CameraStatusServerDisconnected:     "Server disconnected",
+	CameraStatusMigrating:              "Migrating",
+	CameraStatusOthers:                 "Others",
+	CameraStatusStorageRemoved:         "Storage removed",
+	CameraStatusStopping:               "Stopping",
+	CameraStatusConnectionIssueHistory: "Connection issue history",
+	CameraStatusUnauthorized:           "Unauthorized",
+	CameraStatusRTSPError:              "RTSP error",
+	CameraStatusNoVideo:                "No video",
+}
+
+func (s CameraStatus) String() string {
+	if name, ok := cameraStatusNames[s]; ok {
+		return name
+	}
+	return fmt.Sprintf("CameraStatus(%d)", int(s))
+}
+
+// VideoCodec is the video codec a camera streams with
+type VideoCodec int
+
+const (
+	VideoCodecUnknown  VideoCodec = 0
+	VideoCodecMJPEG    VideoCodec = 1
+	VideoCodecMPEG4    VideoCodec = 2
+	VideoCodecH264     VideoCodec = 3
+	VideoCodecMXPEG    VideoCodec = 5
+	VideoCodecH265     VideoCodec = 6
+	VideoCodecH264Plus VideoCodec = 7
+)
+
+var videoCodecNames = map[VideoCodec]string{
+	VideoCodecUnknown:  "Unknown",
+	VideoCodecMJPEG:    "MJPEG",
+	VideoCodecMPEG4:    "MPEG4",
+	VideoCodecH264:     "H.264",
+	VideoCodecMXPEG:    "MxPEG",
+	VideoCodecH265:     "H.265",
+	VideoCodecH264Plus: "H.264+",
+}
+
+func (v VideoCodec) String() string {
+	if name, ok := videoCodecNames[v]; ok {
+		return name
+	}
+	return fmt.Sprintf("VideoCodec(%d)", int(v))
+}
+
+// AudioCodec is the audio codec a camera streams with
+type AudioCodec int
+
+const (
+	AudioCodecUnknown AudioCodec = 0
+	AudioCodecPCM     AudioCodec = 1
+	AudioCodecG711    AudioCodec = 2
+	AudioCodecG726    AudioCodec = 3
+	AudioCodecAAC     AudioCodec = 4
+	AudioCodecAMR     AudioCodec = 5
+)
+
+var audioCodecNames = map[AudioCodec]string{
+	AudioCodecUnknown: "Unknown",
+	AudioCodecPCM:     "PCM",
+	AudioCodecG711:    "G.711",
+	AudioCodecG726:    "G.726",
+	AudioCodecAAC:     "AAC",
+	AudioCodecAMR:     "AMR",
+}
+
+func (a AudioCodec) String() string {
+	if name, ok := audioCodecNames[a]; ok {
+		return name
+	}
+	return fmt.Sprintf("AudioCodec(%d)", int(a))
+}
+
+// BitrateControl is the bitrate control mode of a stream
+type BitrateControl int
+
+const (
+	BitrateControlNone     BitrateControl = 0
+	BitrateControlVariable BitrateControl = 1
+	BitrateControlConstant BitrateControl = 2
+)
+
+var bitrateControlNames = map[BitrateControl]string{
+	BitrateControlNone:     "None",
+	BitrateControlVariable: "Variable",
+	BitrateControlConstant: "Constant",
+}
+
+func (b BitrateControl) String() string {
+	if name, ok := bitrateControlNames[b]; ok {
+		return name
+	}
+	return fmt.Sprintf("BitrateControl(%d)", int(b))
+}
+
+// IsOnline reports whether the camera is connected and working normally
+func (c Camera) IsOnline() bool {
+	return c.Status == CameraStatusNormal
+}
+
+// IsDisabled reports whether the camera has been disabled
+func (c Camera) IsDisabled() bool {
+	return c.Status == CameraStatusDisabled
+}
+
+// CameraResult is the outcome of an operation on a single camera
+type CameraResult struct {
+	ID  int
+	Err error
+}
+
+// EnableCameras enables the cameras with the given IDs
+func (c *SurveillanceStationClient) EnableCameras(ids ...int) ([]CameraResult, error) {
+	return c.EnableCamerasContext(context.Background(), ids...)
+}
+
+// EnableCamerasContext enables the cameras with the given IDs, bound to ctx
+func (c *SurveillanceStationClient) EnableCamerasContext(ctx context.Context, ids ...int) ([]CameraResult, error) {
+	return c.cameraAction(ctx, "Enable", "enable", ids, true)
+}
+
+// DisableCameras disables the cameras with the given IDs
+func (c *SurveillanceStationClient) DisableCameras(ids ...int) ([]CameraResult, error) {
+	return c.DisableCamerasContext(context.Background(), ids...)
+}
+
+// DisableCamerasContext disables the cameras with the given IDs, bound to ctx
+func (c *SurveillanceStationClient) DisableCamerasContext(ctx context.Context, ids ...int) ([]CameraResult, error) {
+	return c.cameraAction(ctx, "Disable", "disable", ids, true)
+}
+
+// DeleteCameras deletes the cameras with the given IDs
+func (c *SurveillanceStationClient) DeleteCameras(ids ...int) ([]CameraResult, error) {
+	return c.DeleteCamerasContext(context.Background(), ids...)
+}
+
+// DeleteCamerasContext deletes the cameras with the given IDs, bound to ctx
+func (c *SurveillanceStationClient) DeleteCamerasContext(ctx context.Context, ids ...int) ([]CameraResult, error) {
+	// A failed batch may already have deleted some cameras, which would then
+	// fail their retry, so delete them one at a time from the start
+	return c.cameraAction(ctx, "Delete", "delete", ids, false)
+}
+
+// cameraAction applies method to the cameras. With batch set it first tries
+// all cameras in a single request. The API fails the whole batch if any
+// camera fails, so in that case every camera is retried on its own to find
+// out which ones are to blame, which is only safe for idempotent methods.
+// Without batch every camera gets its own request. The returned error is
+// non-nil whenever at least one camera failed.
+func (c *SurveillanceStationClient) cameraAction(ctx context.Context, method, verb string, ids []int, batch bool) ([]CameraResult, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	results := make([]CameraResult, len(ids))
+	for i, id := range ids {
+		results[i].ID = id
+	}
+
+	if batch {
+		err := c.callCameraAction(ctx, method, ids)
+		if err == nil {
+			return results, nil
+		}
+
+		// Only an API error is worth isolating, anything else fails every camera
+		var apiErr *APIError
+		if len(ids) == 1 || !errors.As(err, &apiErr) {
+			for i := range results {
+				results[i].Err = err
+			}
+			return results, fmt.Errorf("failed to %s cameras: %w", verb, err)
+		}
+	}
+
+	failed := 0
+	for i := range results {
+		if results[i].Err = c.callCameraAction(ctx, method, []int{results[i].ID}); results[i].Err != nil {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return results, fmt.Errorf("failed to %s %d of %d cameras", verb, failed, len(ids))
+	}
+	return results, nil
+}
+
+func (c *SurveillanceStationClient) callCameraAction(ctx context.Context, method string, ids []int) error {
+	params := url.Values{}
+	params.Set("idList", joinIDs(ids))
+	return c.callAPI(ctx, "SYNO.SurveillanceStation.Camera", method, 3, 9, params, nil)
+}
+
+// ListCamerasOptions narrows down and pages the cameras returned by
+// ListCamerasWithOptions
+type ListCamerasOptions struct {
+	Offset int
+	Limit  int
+	// PrivilegeType only lists cameras the user holds this privilege type
+	// for, zero lists all of them
+	PrivilegeType int
+	// Basic only returns the basic camera fields, which is much faster on
+	// large installations
+	Basic bool
+	// DsID only keeps cameras of this recording server, 0 being the local
+	// host, on CMS setups. Filtering happens on the client.
+	DsID *int
+	// Since only returns cameras changed after this timestamp, as returned in
+	// CameraList.Timestamp by a previous call
+	Since int64
+}
+
+// CameraList is a page of cameras returned by ListCamerasWithOptions
+type CameraList struct {
+	Cameras []Camera
+	// Total is the number of cameras on the server, regardless of paging
+	Total int
+	// Timestamp is the server time of this listing, to be passed as
+	// ListCamerasOptions.Since for an incremental update
+	Timestamp int64
+}
+
+// ListCamerasWithOptions lists the cameras matching opts
+func (c *SurveillanceStationClient) ListCamerasWithOptions(opts *ListCamerasOptions) (*CameraList, error) {
+	return c.ListCamerasWithOptionsContext(context.Background(), opts)
+}
+
+// ListCamerasWithOptionsContext lists the cameras matching opts, bound to ctx
+func (c *SurveillanceStationClient) ListCamerasWithOptionsContext(ctx context.Context, opts *ListCamerasOptions) (*CameraList, error) {
+	if opts == nil {
+		opts = &ListCamerasOptions{}
+	}
+	params := url.Values{}
+	if opts.Offset > 0 {
+		params.Set("offset", strconv.Itoa(opts.Offset))
+	}
+	if opts.Limit > 0 {
+		params.Set("limit", strconv.Itoa(opts.Limit))
+	}
+	if opts.PrivilegeType > 0 {
+		params.Set("privCamType", strconv.Itoa(opts.PrivilegeType))
+	}
+	if opts.Basic {
+		params.Set("basic", "true")
+	}
+	if opts.Since > 0 {
+		params.Set("timestamp", strconv.FormatInt(opts.Since, 10))
+	}
+
+	var data struct {
+		Cameras   []Camera `json:"cameras"`
+		Total     int      `json:"total"`
+		Timestamp int64    `json:"timestamp"`
+	}
+	if err := c.callAPI(ctx, "SYNO.SurveillanceStation.Camera", "List", 1, 9, params, &data); err != nil {
+		return nil, fmt.Errorf("failed to list cameras: %w", err)
+	}
+
+	cameras := data.Cameras
+	if opts.DsID != nil {
+		cameras = cameras[:0]
+		for _, camera := range data.Cameras {
+			if camera.DsID == *opts.DsID {
+				cameras = append(cameras, camera)
+			}
+		}
+	}
+
+	return &CameraList{Cameras: cameras, Total: data.Total, Timestamp: data.Timestamp}, nil
+}
@@ -0,0 +1,219 @@
+package sssg
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// CameraInfoOptions selects the optional blocks returned by GetCameraInfo
+type CameraInfoOptions struct {
+	Basic          bool
+	StreamInfo     bool
+	Optimize       bool
+	PTZ            bool
+	EventDetection bool
+	DeviceOutCap   bool
+}
+
+// PTZCapabilities lists the PTZ features of a camera, where zero means the
+// feature is not supported. Only returned when CameraInfoOptions.PTZ is set.
+type PTZCapabilities struct {
+	PTZCap       int `json:"ptzCap"`
+	PTZPan       int `json:"ptzPan"`
+	PTZTilt      int `json:"ptzTilt"`
+	PTZZoom      int `json:"ptzZoom"`
+	PTZFocus     int `json:"ptzFocus"`
+	PTZIris      int `json:"ptzIris"`
+	PTZAutoFocus int `json:"ptzAutoFocus"`
+	PTZDirection int `json:"ptzDirection"`
+	PTZSpeed     int `json:"ptzSpeed"`
+	PTZHome      int `json:"ptzHome"`
+}
+
+// OptimizeSettings holds the image and stream tuning of a camera. Only
+// returned when CameraInfoOptions.Optimize is set.
+type OptimizeSettings struct {
+	MountType     int  `json:"mountType"`
+	Rotation      int  `json:"rotation"`
+	Flip          bool `json:"flip"`
+	Mirror        bool `json:"mirror"`
+	SmartStream   bool `json:"smartStream"`
+	NightMode     int  `json:"nightMode"`
+	WideDynRange  bool `json:"wdr"`
+	DeFog         bool `json:"defog"`
+	NoiseFilter   int  `json:"noiseFilter"`
+	IRCutSchedule bool `json:"irCutSchedule"`
+}
+
+// DetectionSettings configures one kind of event detection of a camera
+type DetectionSettings struct {
+	Enabled bool `json:"enabled"`
+	// Source is 0 when the camera detects the event itself and 1 when
+	// Surveillance Station analyses the stream
+	Source      int `json:"source"`
+	Sensitivity int `json:"sensitivity"`
+	Threshold   int `json:"threshold"`
+	ObjectSize  int `json:"objectSize"`
+	Percentage  int `json:"percentage"`
+}
+
+// EventDetection holds the detection settings of a camera, nil for the
+// kinds the camera does not support. Only returned when
+// CameraInfoOptions.EventDetection is set.
+type EventDetection struct {
+	Motion    *DetectionSettings `json:"motion,omitempty"`
+	Audio     *DetectionSettings `json:"audio,omitempty"`
+	Tampering *DetectionSettings `json:"tampering,omitempty"`
+}
+
+// DeviceOutputCapabilities lists the outputs a camera can drive. Only
+// returned when CameraInfoOptions.DeviceOutCap is set.
+type DeviceOutputCapabilities struct {
+	AudioOut       bool `json:"audioOut"`
+	DigitalOutputs int  `json:"doNum"`
+	Light          bool `json:"light"`
+	Siren          bool `json:"siren"`
+}
+
+// CameraPrivilege lists what the logged in account may do with a camera
+type CameraPrivilege struct {
+	LiveView bool `json:"liveview"`
+	Playback bool `json:"playback"`
+	Lens     bool `json:"lens"`
+	Audio    bool `json:"audio"`
+}
+
+// CameraDetail is the detailed camera description returned by GetCameraInfo
+type CameraDetail struct {
+	Camera
+	PTZCapabilities
+
+	Stream2 *Stream `json:"stream2,omitempty"`
+	Stream3 *Stream `json:"stream3,omitempty"`
+
+	// Optional blocks, nil unless requested through CameraInfoOptions and
+	// returned by the NAS
+	Optimize       *OptimizeSettings         `json:"optimize,omitempty"`
+	EventDetection *EventDetection           `json:"eventDetection,omitempty"`
+	DeviceOutCap   *DeviceOutputCapabilities `json:"deviceOutCap,omitempty"`
+	Privilege      *CameraPrivilege          `json:"privilege,omitempty"`
+
+	// Extra holds every field of the response not decoded above, whose
+	// layout varies between Surveillance Station releases and camera models
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+var (
+	cameraDetailFieldsOnce sync.Once
+	cameraDetailFields     map[string]bool
+)
+
+// jsonFieldNames collects the JSON names of t's fields, descending into
+// embedded structs the way encoding/json does
+func jsonFieldNames(t reflect.Type, names map[string]bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if field.Anonymous && tag == "" {
+			jsonFieldNames(field.Type, names)
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		names[name] = true
+	}
+}
+
+// UnmarshalJSON decodes the known fields of the camera and keeps the
+// remaining ones in Extra
+func (d *CameraDetail) UnmarshalJSON(data []byte) error {
+	type plain CameraDetail
+	var detail plain
+	if err := json.Unmarshal(data, &detail); err != nil {
+		return err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	cameraDetailFieldsOnce.Do(func() {
+		cameraDetailFields = map[string]bool{}
+		jsonFieldNames(reflect.TypeOf(detail), cameraDetailFields)
+	})
+	for name := range fields {
+		if cameraDetailFields[name] {
+			delete(fields, name)
+		}
+	}
+	if len(fields) > 0 {
+		detail.Extra = fields
+	}
+
+	*d = CameraDetail(detail)
+	return nil
+}
+
+// ExtraField decodes the undecoded response field name into v and reports
+// whether the field was present
+func (d *CameraDetail) ExtraField(name string, v interface{}) (bool, error) {
+	raw, ok := d.Extra[name]
+	if !ok {
+		return false, nil
+	}
+	return true, json.Unmarshal(raw, v)
+}
+
+// Stream returns the stream with the given number, as referenced by
+// HighProfileStreamNo, MediumProfileStreamNo and LowProfileStreamNo
+func (d *CameraDetail) Stream(no int) (Stream, bool) {
+	switch {
+	case no == 1:
+		return d.Stream1, true
+	case no == 2 && d.Stream2 != nil:
+		return *d.Stream2, true
+	case no == 3 && d.Stream3 != nil:
+		return *d.Stream3, true
+	}
+	return Stream{}, false
+}
+
+// GetCameraInfo returns the detailed description of the given cameras
+func (c *SurveillanceStationClient) GetCameraInfo(ids []int, opts *CameraInfoOptions) ([]CameraDetail, error) {
+	return c.GetCameraInfoContext(context.Background(), ids, opts)
+}
+
+// GetCameraInfoContext returns the detailed description of the given cameras, bound to ctx
+func (c *SurveillanceStationClient) GetCameraInfoContext(ctx context.Context, ids []int, opts *CameraInfoOptions) ([]CameraDetail, error) {
+	if opts == nil {
+		opts = &CameraInfoOptions{}
+	}
+	params := url.Values{}
+	params.Set("cameraIds", joinIDs(ids))
+	params.Set("basic", strconv.FormatBool(opts.Basic))
+	params.Set("streamInfo", strconv.FormatBool(opts.StreamInfo))
+	params.Set("optimize", strconv.FormatBool(opts.Optimize))
+	params.Set("ptz", strconv.FormatBool(opts.PTZ))
+	params.Set("eventDetection", strconv.FormatBool(opts.EventDetection))
+	params.Set("deviceOutCap", strconv.FormatBool(opts.DeviceOutCap))
+
+	var data struct {
+		Cameras []CameraDetail `json:"cameras"`
+	}
+	if err := c.callAPI(ctx, "SYNO.SurveillanceStation.Camera", "GetInfo", 1, 8, params, &data); err != nil {
+		return nil, fmt.Errorf("failed to get camera info: %w", err)
+	}
+
+	return data.Cameras, nil
+}
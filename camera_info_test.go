@@ -0,0 +1,72 @@
+package sssg
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestGetCameraInfo(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("method") != "GetInfo" || query.Get("cameraIds") != "3,4" || query.Get("ptz") != "true" {
+			t.Errorf("Unexpected query: %v", query)
+		}
+		w.Write([]byte(`{"success":true,"data":{"cameras":[{
+			"id":3,
+			"newName":"Driveway",
+			"status":1,
+			"stream1":{"fps":15,"resolution":"2560x1440"},
+			"stream2":{"fps":10,"resolution":"640x360"},
+			"ptzPan":1,
+			"ptzZoom":1,
+			"optimize":{"rotation":180,"smartStream":true},
+			"eventDetection":{"motion":{"enabled":true,"sensitivity":80,"unknownSetting":1}},
+			"deviceOutCap":{"audioOut":true,"doNum":1},
+			"privilege":{"liveview":true,"playback":false},
+			"someFutureField":true
+		}]}}`))
+	})
+
+	details, err := client.GetCameraInfo([]int{3, 4}, &CameraInfoOptions{PTZ: true})
+	if err != nil {
+		t.Fatalf("Failed to get camera info: %v", err)
+	}
+	if len(details) != 1 {
+		t.Fatalf("Expected one camera, got %d", len(details))
+	}
+
+	detail := details[0]
+	if detail.ID != 3 || detail.NewName != "Driveway" || !detail.IsOnline() {
+		t.Errorf("Unexpected camera: %+v", detail.Camera)
+	}
+	if detail.PTZPan != 1 || detail.PTZZoom != 1 || detail.PTZFocus != 0 {
+		t.Errorf("Unexpected PTZ capabilities: %+v", detail.PTZCapabilities)
+	}
+	if stream, ok := detail.Stream(2); !ok || stream.Resolution != "640x360" {
+		t.Errorf("Unexpected stream 2: %+v", stream)
+	}
+	if _, ok := detail.Stream(3); ok {
+		t.Errorf("Expected stream 3 to be missing")
+	}
+
+	if detail.Optimize == nil || detail.Optimize.Rotation != 180 || !detail.Optimize.SmartStream {
+		t.Errorf("Unexpected optimize settings: %+v", detail.Optimize)
+	}
+	if detail.EventDetection == nil || detail.EventDetection.Motion == nil || detail.EventDetection.Motion.Sensitivity != 80 || detail.EventDetection.Audio != nil {
+		t.Errorf("Unexpected event detection: %+v", detail.EventDetection)
+	}
+	if detail.DeviceOutCap == nil || !detail.DeviceOutCap.AudioOut || detail.DeviceOutCap.DigitalOutputs != 1 {
+		t.Errorf("Unexpected device outputs: %+v", detail.DeviceOutCap)
+	}
+	if detail.Privilege == nil || !detail.Privilege.LiveView || detail.Privilege.Playback {
+		t.Errorf("Unexpected privilege: %+v", detail.Privilege)
+	}
+
+	if len(detail.Extra) != 1 {
+		t.Errorf("Expected only unknown fields in Extra, got %v", detail.Extra)
+	}
+	var future bool
+	if ok, err := detail.ExtraField("someFutureField", &future); !ok || err != nil || !future {
+		t.Errorf("Unexpected future field: %v %v %v", future, ok, err)
+	}
+}
@@ -2,6 +2,8 @@ package sssg
 
 import (
 	"encoding/json"
+	"net/http"
+	"strings"
 	"testing"
 )
 
@@ -89,3 +91,107 @@ func deepEqual(a, b map[string]interface{}) bool {
 	reEncoded, _ := json.Marshal(b)
 	return string(original) == string(reEncoded)
 }
+
+func TestCameraEnums(t *testing.T) {
+	var camera Camera
+	if err := json.Unmarshal([]byte(`{"status":1,"videoCodec":6,"audioCodec":4,"stream1":{"bitrateCtrl":2}}`), &camera); err != nil {
+		t.Fatalf("Failed to parse JSON: %v", err)
+	}
+
+	if camera.Status != CameraStatusNormal || !camera.IsOnline() || camera.IsDisabled() {
+		t.Errorf("Unexpected status: %v", camera.Status)
+	}
+	if camera.VideoCodec.String() != "H.265" || camera.AudioCodec.String() != "AAC" || camera.Stream1.BitrateCtrl.String() != "Constant" {
+		t.Errorf("Unexpected codecs: %v %v %v", camera.VideoCodec, camera.AudioCodec, camera.Stream1.BitrateCtrl)
+	}
+	if got := CameraStatus(99).String(); got != "CameraStatus(99)" {
+		t.Errorf("Unexpected unknown status: %s", got)
+	}
+
+	encoded, err := json.Marshal(Camera{Status: CameraStatusDisabled})
+	if err != nil {
+		t.Fatalf("Failed to encode JSON: %v", err)
+	}
+	if !strings.Contains(string(encoded), `"status":7`) {
+		t.Errorf("Expected status to be encoded as an integer: %s", encoded)
+	}
+}
+
+func TestDisableCamerasPartialFailure(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("method") != "Disable" {
+			t.Errorf("Unexpected method: %s", query.Get("method"))
+		}
+		if strings.Contains(query.Get("idList"), "2") {
+			w.Write([]byte(`{"success":false,"error":{"code":400}}`))
+			return
+		}
+		w.Write([]byte(`{"success":true}`))
+	})
+
+	results, err := client.DisableCameras(1, 2, 3)
+	if err == nil {
+		t.Fatalf("Expected an error for the partial failure")
+	}
+	if len(results) != 3 {
+		t.Fatalf("Expected 3 results, got %d", len(results))
+	}
+	for _, result := range results {
+		if failed := result.Err != nil; failed != (result.ID == 2) {
+			t.Errorf("Unexpected result for camera %d: %v", result.ID, result.Err)
+		}
+	}
+}
+
+func TestDeleteCamerasOneByOne(t *testing.T) {
+	cameras := map[string]bool{"1": true, "2": true, "3": true}
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("method") != "Delete" {
+			t.Errorf("Unexpected method: %s", query.Get("method"))
+		}
+		id := query.Get("idList")
+		if !cameras[id] || id == "2" {
+			w.Write([]byte(`{"success":false,"error":{"code":400}}`))
+			return
+		}
+		delete(cameras, id)
+		w.Write([]byte(`{"success":true}`))
+	})
+
+	results, err := client.DeleteCameras(1, 2, 3)
+	if err == nil {
+		t.Fatalf("Expected an error for the partial failure")
+	}
+	for _, result := range results {
+		if failed := result.Err != nil; failed != (result.ID == 2) {
+			t.Errorf("Unexpected result for camera %d: %v", result.ID, result.Err)
+		}
+	}
+	if len(cameras) != 1 || !cameras["2"] {
+		t.Errorf("Expected only camera 2 to remain, got %v", cameras)
+	}
+}
+
+func TestListCamerasWithOptions(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("offset") != "10" || query.Get("limit") != "5" || query.Get("basic") != "true" || query.Get("timestamp") != "1700000000" {
+			t.Errorf("Unexpected query: %v", query)
+		}
+		w.Write([]byte(`{"success":true,"data":{"total":120,"timestamp":1700000042,"cameras":[{"id":11,"dsId":0},{"id":12,"dsId":2}]}}`))
+	})
+
+	dsID := 2
+	list, err := client.ListCamerasWithOptions(&ListCamerasOptions{Offset: 10, Limit: 5, Basic: true, DsID: &dsID, Since: 1700000000})
+	if err != nil {
+		t.Fatalf("Failed to list cameras: %v", err)
+	}
+	if list.Total != 120 || list.Timestamp != 1700000042 {
+		t.Errorf("Unexpected list metadata: %+v", list)
+	}
+	if len(list.Cameras) != 1 || list.Cameras[0].ID != 12 {
+		t.Errorf("Expected only camera 12, got %+v", list.Cameras)
+	}
+}
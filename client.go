@@ -1,12 +1,13 @@
 package sssg
 
 import (
+	"context"
 	"crypto/tls"
-	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
 	"net/url"
+	"sync"
+	"time"
 )
 
 type SurveillanceStationClient struct {
@@ -15,54 +16,73 @@ type SurveillanceStationClient struct {
 	Username string
 	Password string
 	Client   *http.Client
+
+	// TOTPSecret is the base32 secret of an account with 2-step verification,
+	// used to generate a one-time code whenever the client logs in
+	TOTPSecret string
+	// DeviceName asks DSM to remember this client as a trusted device after
+	// a successful 2-step login, so later logins can skip the one-time code
+	DeviceName string
+	// DeviceID is the trusted device token returned by DSM, sent along with
+	// every login once known
+	DeviceID string
+
+	mu              sync.RWMutex // guards Session, loginTime and the keep-alive
+	loginMu         sync.Mutex   // serializes automatic re-logins
+	loginTime       time.Time
+	keepAliveStop   chan struct{}
+	keepAliveCancel context.CancelFunc // aborts a refresh in flight
+	keepAliveDone   chan struct{}
+	infoMu          sync.Mutex // guards apiInfo
+	apiInfo         map[string]APIInfo
 }
 
 type Stream struct {
-	BitrateCtrl     int    `json:"bitrateCtrl"`
-	ConstantBitrate string `json:"constantBitrate"`
-	FPS             int    `json:"fps"`
-	Quality         string `json:"quality"`
-	Resolution      string `json:"resolution"`
+	BitrateCtrl     BitrateControl `json:"bitrateCtrl"`
+	ConstantBitrate string         `json:"constantBitrate"`
+	FPS             int            `json:"fps"`
+	Quality         string         `json:"quality"`
+	Resolution      string         `json:"resolution"`
 }
 
 type Camera struct {
-	DINum                   int    `json:"DINum"`
-	DONum                   int    `json:"DONum"`
-	AddedTime               int    `json:"addedTime"`
-	AudioCodec              int    `json:"audioCodec"`
-	Channel                 string `json:"channel"`
-	ConnectionOverSSL       bool   `json:"connectionOverSSL"`
-	DsID                    int    `json:"dsId"`
-	DsName                  string `json:"dsName"`
-	EnableLowProfile        bool   `json:"enableLowProfile"`
-	EnableRecordingKeepDays bool   `json:"enableRecordingKeepDays"`
-	EnableRecordingKeepSize bool   `json:"enableRecordingKeepSize"`
-	EnableSRTP              bool   `json:"enableSRTP"`
-	FOV                     string `json:"fov"`
-	HighProfileStreamNo     int    `json:"highProfileStreamNo"`
-	ID                      int    `json:"id"`
-	IDOnRecServer           int    `json:"idOnRecServer"`
-	IP                      string `json:"ip"`
-	LowProfileStreamNo      int    `json:"lowProfileStreamNo"`
-	MAC                     string `json:"mac"`
-	MediumProfileStreamNo   int    `json:"mediumProfileStreamNo"`
-	Model                   string `json:"model"`
-	NewName                 string `json:"newName"`
-	Port                    int    `json:"port"`
-	PostRecordTime          int    `json:"postRecordTime"`
-	PreRecordTime           int    `json:"preRecordTime"`
-	RecordPrefix            string `json:"recordPrefix"`
-	RecordSchedule          string `json:"recordSchedule"`
-	RecordTime              int    `json:"recordTime"`
-	RecordingKeepDays       int    `json:"recordingKeepDays"`
-	RecordingKeepSize       string `json:"recordingKeepSize"`
-	Status                  int    `json:"status"`
-	Stream1                 Stream `json:"stream1"`
-	TVStandard              int    `json:"tvStandard"`
-	UserName                string `json:"userName"`
-	Vendor                  string `json:"vendor"`
-	VideoCodec              int    `json:"videoCodec"`
-	VideoMode               string `json:"videoMode"`
+	DINum                   int          `json:"DINum"`
+	DONum                   int          `json:"DONum"`
+	AddedTime               int          `json:"addedTime"`
+	AudioCodec              AudioCodec   `json:"audioCodec"`
+	Channel                 string       `json:"channel"`
+	ConnectionOverSSL       bool         `json:"connectionOverSSL"`
+	DsID                    int          `json:"dsId"`
+	DsName                  string       `json:"dsName"`
+	EnableLowProfile        bool         `json:"enableLowProfile"`
+	EnableRecordingKeepDays bool         `json:"enableRecordingKeepDays"`
+	EnableRecordingKeepSize bool         `json:"enableRecordingKeepSize"`
+	EnableSRTP              bool         `json:"enableSRTP"`
+	FOV                     string       `json:"fov"`
+	HighProfileStreamNo     int          `json:"highProfileStreamNo"`
+	ID                      int          `json:"id"`
+	IDOnRecServer           int          `json:"idOnRecServer"`
+	IP                      string       `json:"ip"`
+	LowProfileStreamNo      int          `json:"lowProfileStreamNo"`
+	MAC                     string       `json:"mac"`
+	MediumProfileStreamNo   int          `json:"mediumProfileStreamNo"`
+	Model                   string       `json:"model"`
+	NewName                 string       `json:"newName"`
+	Port                    int          `json:"port"`
+	PostRecordTime          int          `json:"postRecordTime"`
+	PreRecordTime           int          `json:"preRecordTime"`
+	RecordPrefix            string       `json:"recordPrefix"`
+	RecordSchedule          string       `json:"recordSchedule"`
+	RecordTime              int          `json:"recordTime"`
+	RecordingKeepDays       int          `json:"recordingKeepDays"`
+	RecordingKeepSize       string       `json:"recordingKeepSize"`
+	Status                  CameraStatus `json:"status"`
+	Stream1                 Stream       `json:"stream1"`
+	TVStandard              int          `json:"tvStandard"`
+	UserName                string       `json:"userName"`
+	Vendor                  string       `json:"vendor"`
+	VideoCodec              VideoCodec   `json:"videoCodec"`
+	VideoMode               string       `json:"videoMode"`
 }
 
 func NewClient(baseURL, username, password string, insecureSkipVerify bool) *SurveillanceStationClient {
@@ -82,121 +102,105 @@ func NewClient(baseURL, username, password string, insecureSkipVerify bool) *Sur
 
 // Login to the Surveillance Station
 func (c *SurveillanceStationClient) Login() error {
-	endpoint := fmt.Sprintf("%s/webapi/SurveillanceStation/ThirdParty/Auth/Login/v1", c.BaseURL)
-	params := url.Values{}
-	params.Set("account", c.Username)
-	params.Set("passwd", c.Password)
+	return c.LoginContext(context.Background())
+}
+
+// LoginContext logs in to the Surveillance Station, bound to ctx
+func (c *SurveillanceStationClient) LoginContext(ctx context.Context) error {
+	return c.login(ctx, "")
+}
+
+// LoginOTP logs in to the Surveillance Station with a 2-step verification code
+func (c *SurveillanceStationClient) LoginOTP(otpCode string) error {
+	return c.LoginOTPContext(context.Background(), otpCode)
+}
+
+// LoginOTPContext logs in with a 2-step verification code, bound to ctx
+func (c *SurveillanceStationClient) LoginOTPContext(ctx context.Context, otpCode string) error {
+	return c.login(ctx, otpCode)
+}
 
-	resp, err := c.Client.Get(endpoint + "?" + params.Encode())
+// login logs in using otpCode, or a code generated from TOTPSecret when
+// otpCode is empty, and the trusted device settings of the client
+func (c *SurveillanceStationClient) login(ctx context.Context, otpCode string) error {
+	endpoint, api, params, err := c.authRequest(ctx, "Login")
 	if err != nil {
-		return err
+		return fmt.Errorf("login failed: %w", err)
 	}
-	defer resp.Body.Close()
+	params.Set("account", c.Username)
+	params.Set("passwd", c.Password)
 
-	var result struct {
-		Data struct {
-			Sid string `json:"sid"`
-		} `json:"data"`
-		Success bool `json:"success"`
+	if otpCode == "" && c.TOTPSecret != "" {
+		code, err := GenerateTOTP(c.TOTPSecret, time.Now())
+		if err != nil {
+			return fmt.Errorf("login failed: %w", err)
+		}
+		otpCode = code
 	}
-	err = json.NewDecoder(resp.Body).Decode(&result)
-	if err != nil {
-		return err
+	if otpCode != "" {
+		params.Set("otp_code", otpCode)
+	}
+	if c.DeviceID != "" {
+		params.Set("device_id", c.DeviceID)
+	} else if c.DeviceName != "" {
+		params.Set("enable_device_token", "yes")
+		params.Set("device_name", c.DeviceName)
+	}
+
+	var data struct {
+		Sid string `json:"sid"`
+		Did string `json:"did"`
 	}
-	if !result.Success {
-		return fmt.Errorf("login failed")
+	if err := c.do(ctx, endpoint, api, "Login", params, &data); err != nil {
+		return fmt.Errorf("login failed: %w", err)
 	}
 
-	c.Session = result.Data.Sid
+	if data.Did != "" {
+		c.DeviceID = data.Did
+	}
+	c.setSession(data.Sid)
 	return nil
 }
 
 // Get Home Mode Info
 func (c *SurveillanceStationClient) GetHomeModeInfo() (*HomeModeInfo, error) {
-	endpoint := fmt.Sprintf("%s/webapi/entry.cgi", c.BaseURL)
+	return c.GetHomeModeInfoContext(context.Background())
+}
+
+// GetHomeModeInfoContext retrieves the Home Mode info, bound to ctx
+func (c *SurveillanceStationClient) GetHomeModeInfoContext(ctx context.Context) (*HomeModeInfo, error) {
 	params := url.Values{}
-	params.Set("api", "SYNO.SurveillanceStation.HomeMode")
-	params.Set("method", "GetInfo")
-	params.Set("version", "1")
 	params.Set("need_mobiles", "true")
-	params.Set("_sid", c.Session)
-
-	resp, err := c.Client.Get(endpoint + "?" + params.Encode())
-	if err != nil {
-		return nil, fmt.Errorf("failed to retrieve home mode info: %v", err)
-	}
-	defer resp.Body.Close()
-
-	var result struct {
-		Success bool         `json:"success"`
-		Data    HomeModeInfo `json:"data"`
-	}
-	err = json.NewDecoder(resp.Body).Decode(&result)
-	if err != nil {
-		return nil, fmt.Errorf("failed to decode home mode info response: %v", err)
-	}
 
-	if !result.Success {
-		return nil, fmt.Errorf("failed to retrieve home mode info")
+	var data HomeModeInfo
+	if err := c.callAPI(ctx, "SYNO.SurveillanceStation.HomeMode", "GetInfo", 1, 1, params, &data); err != nil {
+		return nil, fmt.Errorf("failed to retrieve home mode info: %w", err)
 	}
 
-	return &result.Data, nil
+	return &data, nil
 }
 
 // List available cameras and return them
 func (c *SurveillanceStationClient) ListCameras() ([]Camera, error) {
-	endpoint := fmt.Sprintf("%s/webapi/entry.cgi", c.BaseURL)
-	params := url.Values{}
-	params.Set("api", "SYNO.SurveillanceStation.Camera")
-	params.Set("method", "List")
-	params.Set("version", "9")
-	params.Set("_sid", c.Session)
-
-	resp, err := c.Client.Get(endpoint + "?" + params.Encode())
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
+	return c.ListCamerasContext(context.Background())
+}
 
-	var result struct {
-		Success bool `json:"success"`
-		Data    struct {
-			Cameras []Camera `json:"cameras"`
-		} `json:"data"`
-	}
-	err = json.NewDecoder(resp.Body).Decode(&result)
+// ListCamerasContext lists the available cameras, bound to ctx
+func (c *SurveillanceStationClient) ListCamerasContext(ctx context.Context) ([]Camera, error) {
+	list, err := c.ListCamerasWithOptionsContext(ctx, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	if !result.Success {
-		return nil, fmt.Errorf("failed to list cameras")
-	}
-
-	return result.Data.Cameras, nil
+	return list.Cameras, nil
 }
 
 // TakeSnapshot returns the camera snapshot as bytes
 func (c *SurveillanceStationClient) TakeSnapshot(camera Camera) ([]byte, error) {
-	endpoint := fmt.Sprintf("%s/webapi/entry.cgi", c.BaseURL)
-	params := url.Values{}
-	params.Set("api", "SYNO.SurveillanceStation.Camera")
-	params.Set("method", "GetSnapshot")
-	params.Set("version", "9")
-	params.Set("id", fmt.Sprintf("%d", camera.ID))
-	params.Set("_sid", c.Session)
-
-	resp, err := c.Client.Get(endpoint + "?" + params.Encode())
-	if err != nil {
-		return nil, fmt.Errorf("failed to take snapshot for camera ID %d: %v", camera.ID, err)
-	}
-	defer resp.Body.Close()
-
-	// Read the image data into a byte slice
-	imgData, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read snapshot data for camera ID %d: %v", camera.ID, err)
-	}
+	return c.TakeSnapshotContext(context.Background(), camera)
+}
 
-	return imgData, nil
+// TakeSnapshotContext returns the camera snapshot as bytes, bound to ctx
+func (c *SurveillanceStationClient) TakeSnapshotContext(ctx context.Context, camera Camera) ([]byte, error) {
+	return c.TakeSnapshotWithOptionsContext(ctx, camera, nil)
 }
@@ -0,0 +1,147 @@
+package sssg
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testAPIInfo is the SYNO.API.Info response served to test clients
+const testAPIInfo = `{"success":true,"data":{
+	"SYNO.API.Info":{"path":"query.cgi","minVersion":1,"maxVersion":1},
+	"SYNO.SurveillanceStation.Camera":{"path":"entry.cgi","minVersion":1,"maxVersion":9},
+	"SYNO.SurveillanceStation.Info":{"path":"entry.cgi","minVersion":1,"maxVersion":8},
+	"SYNO.SurveillanceStation.HomeMode":{"path":"entry.cgi","minVersion":1,"maxVersion":1},
+	"SYNO.SurveillanceStation.PTZ":{"path":"entry.cgi","minVersion":1,"maxVersion":5},
+	"SYNO.SurveillanceStation.PTZ.Preset":{"path":"entry.cgi","minVersion":1,"maxVersion":1},
+	"SYNO.SurveillanceStation.PTZ.Patrol":{"path":"entry.cgi","minVersion":1,"maxVersion":1},
+	"SYNO.SurveillanceStation.Stream.VideoStreaming":{"path":"entry.cgi","minVersion":1,"maxVersion":1},
+	"SYNO.SurveillanceStation.Recording":{"path":"entry.cgi","minVersion":1,"maxVersion":6},
+	"SYNO.SurveillanceStation.ExternalRecording":{"path":"entry.cgi","minVersion":1,"maxVersion":3},
+	"SYNO.SurveillanceStation.Event":{"path":"entry.cgi","minVersion":1,"maxVersion":5}
+}}`
+
+// newTestClient returns a client talking to a test server backed by handler.
+// API info queries are answered with testAPIInfo.
+func newTestClient(t *testing.T, handler http.HandlerFunc) *SurveillanceStationClient {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/query.cgi") {
+			w.Write([]byte(testAPIInfo))
+			return
+		}
+		handler(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	client := NewClient(server.URL, "user", "pass", false)
+	client.Client = server.Client()
+	return client
+}
+
+func TestListCamerasContext(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("method"); got != "List" {
+			t.Errorf("Unexpected method: %s", got)
+		}
+		w.Write([]byte(`{"success":true,"data":{"cameras":[{"id":1,"newName":"Front"},{"id":2,"newName":"Back"}]}}`))
+	})
+
+	cameras, err := client.ListCamerasContext(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to list cameras: %v", err)
+	}
+	if len(cameras) != 2 || cameras[1].NewName != "Back" {
+		t.Errorf("Unexpected cameras: %+v", cameras)
+	}
+}
+
+func TestContextCancellation(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := client.GetHomeModeInfoContext(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected deadline exceeded, got: %v", err)
+	}
+}
+
+func TestReloginOnExpiredSession(t *testing.T) {
+	var logins int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/Login/v1") {
+			atomic.AddInt32(&logins, 1)
+			time.Sleep(10 * time.Millisecond)
+			w.Write([]byte(`{"success":true,"data":{"sid":"fresh"}}`))
+			return
+		}
+		if r.URL.Query().Get("_sid") != "fresh" {
+			w.Write([]byte(`{"success":false,"error":{"code":119}}`))
+			return
+		}
+		w.Write([]byte(`{"success":true,"data":{"cameras":[]}}`))
+	})
+	client.Session = "stale"
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.ListCameras(); err != nil {
+				t.Errorf("Failed to list cameras: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := atomic.LoadInt32(&logins); got != 1 {
+		t.Errorf("Expected a single re-login, got %d", got)
+	}
+}
+
+func TestLoginWithOTPAndTrustedDevice(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		switch {
+		case query.Get("device_id") == "device-token":
+			w.Write([]byte(`{"success":true,"data":{"sid":"trusted"}}`))
+		case query.Get("otp_code") == "123456" && query.Get("enable_device_token") == "yes":
+			w.Write([]byte(`{"success":true,"data":{"sid":"otp","did":"device-token"}}`))
+		default:
+			w.Write([]byte(`{"success":false,"error":{"code":403}}`))
+		}
+	})
+	client.DeviceName = "poller"
+
+	if err := client.Login(); !errors.Is(err, ErrOTPRequired) {
+		t.Fatalf("Expected ErrOTPRequired, got: %v", err)
+	}
+
+	if err := client.LoginOTP("123456"); err != nil {
+		t.Fatalf("OTP login failed: %v", err)
+	}
+	if client.DeviceID != "device-token" {
+		t.Errorf("Expected device token to be stored, got %q", client.DeviceID)
+	}
+
+	if err := client.Login(); err != nil {
+		t.Fatalf("Trusted device login failed: %v", err)
+	}
+	if client.Session != "trusted" {
+		t.Errorf("Unexpected session: %s", client.Session)
+	}
+}
@@ -0,0 +1,123 @@
+package sssg
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrSessionExpired is matched by API errors caused by a timed out,
+	// interrupted or unknown session id
+	ErrSessionExpired = errors.New("session expired")
+	// ErrPermissionDenied is matched by API errors caused by insufficient
+	// privileges of the logged in account
+	ErrPermissionDenied = errors.New("permission denied")
+	// ErrOTPRequired is matched by login errors asking for a 2-step
+	// verification code
+	ErrOTPRequired = errors.New("2-step verification code required")
+	// ErrAPIUnsupported is returned when the NAS does not provide an API, or
+	// none of the versions this client implements
+	ErrAPIUnsupported = errors.New("API not supported")
+)
+
+// APIError is returned when the WebAPI responds with success set to false
+type APIError struct {
+	Code   int
+	API    string
+	Method string
+}
+
+// Generic error codes shared by all Synology WebAPIs
+var commonErrorMessages = map[int]string{
+	100: "Unknown error",
+	101: "Invalid parameter",
+	102: "The requested API does not exist",
+	103: "The requested method does not exist",
+	104: "The requested version does not support the functionality",
+	105: "The logged in session does not have permission",
+	106: "Session timeout",
+	107: "Session interrupted by duplicate login",
+	108: "Failed to upload the file",
+	109: "The network connection is unstable or the system is busy",
+	110: "The network connection is unstable or the system is busy",
+	111: "The network connection is unstable or the system is busy",
+	114: "Lost parameters for this API",
+	115: "Not allowed to upload a file",
+	116: "Not allowed to perform for a demo site",
+	117: "The network connection is unstable or the system is busy",
+	118: "The network connection is unstable or the system is busy",
+	119: "Invalid session",
+}
+
+// Error codes returned by the authentication APIs
+var authErrorMessages = map[int]string{
+	400: "No such account or incorrect password",
+	401: "Account disabled",
+	402: "Permission denied",
+	403: "2-step verification code required",
+	404: "Failed to authenticate 2-step verification code",
+	406: "Enforce to authenticate with 2-factor authentication code",
+	407: "Blocked IP source",
+	408: "Expired password cannot change",
+	409: "Expired password",
+	410: "Password must be changed",
+}
+
+// Error codes returned by the SYNO.SurveillanceStation.* APIs
+var surveillanceErrorMessages = map[int]string{
+	400: "Execution failed",
+	401: "Invalid parameter",
+	402: "Camera disabled",
+	403: "Insufficient license",
+	404: "Codec activation failed",
+	405: "CMS server connection failed",
+	407: "CMS closed",
+	410: "Camera is not supported",
+	412: "Need to add license",
+	413: "Reach the maximum of platform",
+	414: "Some events not exist",
+	415: "Message connect failed",
+	417: "Test connection error",
+	418: "Object/VisualStation ID does not exist",
+	419: "VisualStation name repetition",
+	439: "Too many items selected",
+}
+
+// isAuthAPI reports whether api refers to one of the authentication APIs,
+// whose 4xx codes mean something different from the Surveillance ones
+func isAuthAPI(api string) bool {
+	return api == "SYNO.API.Auth" || strings.HasSuffix(api, "Auth")
+}
+
+// Message returns a human-readable description of the error code
+func (e *APIError) Message() string {
+	if msg, ok := commonErrorMessages[e.Code]; ok {
+		return msg
+	}
+	table := surveillanceErrorMessages
+	if isAuthAPI(e.API) {
+		table = authErrorMessages
+	}
+	if msg, ok := table[e.Code]; ok {
+		return msg
+	}
+	return "Unknown error"
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%s %s failed with code %d: %s", e.API, e.Method, e.Code, e.Message())
+}
+
+// Is allows APIError to be matched against the package sentinel errors
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrSessionExpired:
+		return e.Code == 106 || e.Code == 107 || e.Code == 119
+	case ErrPermissionDenied:
+		return e.Code == 105 || (isAuthAPI(e.API) && e.Code == 402)
+	case ErrOTPRequired:
+		return isAuthAPI(e.API) && (e.Code == 403 || e.Code == 406)
+	}
+	return false
+}
@@ -0,0 +1,62 @@
+package sssg
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestAPIErrorSentinels(t *testing.T) {
+	testCases := []struct {
+		name    string
+		err     *APIError
+		target  error
+		matches bool
+	}{
+		{"Session timeout", &APIError{Code: 106, API: "SYNO.SurveillanceStation.Camera"}, ErrSessionExpired, true},
+		{"Invalid session", &APIError{Code: 119, API: "SYNO.SurveillanceStation.Camera"}, ErrSessionExpired, true},
+		{"Insufficient privilege", &APIError{Code: 105, API: "SYNO.SurveillanceStation.Camera"}, ErrPermissionDenied, true},
+		{"Auth permission denied", &APIError{Code: 402, API: "SYNO.API.Auth"}, ErrPermissionDenied, true},
+		{"Camera disabled is not permission denied", &APIError{Code: 402, API: "SYNO.SurveillanceStation.Camera"}, ErrPermissionDenied, false},
+		{"OTP required", &APIError{Code: 403, API: "SYNO.SurveillanceStation.ThirdParty.Auth"}, ErrOTPRequired, true},
+		{"Insufficient license is not OTP", &APIError{Code: 403, API: "SYNO.SurveillanceStation.Camera"}, ErrOTPRequired, false},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			if got := errors.Is(testCase.err, testCase.target); got != testCase.matches {
+				t.Errorf("errors.Is(%v, %v) = %v, expected %v", testCase.err, testCase.target, got, testCase.matches)
+			}
+		})
+	}
+}
+
+func TestAPIErrorMessage(t *testing.T) {
+	authErr := &APIError{Code: 400, API: "SYNO.API.Auth", Method: "Login"}
+	if got := authErr.Message(); got != "No such account or incorrect password" {
+		t.Errorf("Unexpected auth message: %s", got)
+	}
+
+	cameraErr := &APIError{Code: 400, API: "SYNO.SurveillanceStation.Camera", Method: "List"}
+	if got := cameraErr.Message(); got != "Execution failed" {
+		t.Errorf("Unexpected camera message: %s", got)
+	}
+}
+
+func TestAPIErrorFromResponse(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"success":false,"error":{"code":105}}`))
+	})
+
+	_, err := client.ListCameras()
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Expected an APIError, got: %v", err)
+	}
+	if apiErr.Code != 105 || apiErr.API != "SYNO.SurveillanceStation.Camera" || apiErr.Method != "List" {
+		t.Errorf("Unexpected APIError: %+v", apiErr)
+	}
+	if !errors.Is(err, ErrPermissionDenied) {
+		t.Errorf("Expected error to match ErrPermissionDenied")
+	}
+}
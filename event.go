@@ -0,0 +1,198 @@
+package sssg
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// ErrNoRecording is returned when an event has no recording on the NAS,
+// for instance because it was rotated away
+var ErrNoRecording = errors.New("no recording found for event")
+
+// Event is a single alert raised by a camera
+type Event struct {
+	ID         int             `json:"id"`
+	CameraID   int             `json:"cameraId"`
+	CameraName string          `json:"camera_name"`
+	StartTime  int64           `json:"startTime"`
+	StopTime   int64           `json:"stopTime"`
+	Reason     RecordingReason `json:"reason"`
+	Read       bool            `json:"read"`
+	Locked     bool            `json:"locked"`
+}
+
+// Start returns the start time of the event
+func (e Event) Start() time.Time {
+	return time.Unix(e.StartTime, 0)
+}
+
+// Stop returns the stop time of the event, or the zero time while the event
+// is still ongoing
+func (e Event) Stop() time.Time {
+	if e.StopTime == 0 {
+		return time.Time{}
+	}
+	return time.Unix(e.StopTime, 0)
+}
+
+// ListEventsOptions narrows down and pages the events returned by
+// ListEvents
+type ListEventsOptions struct {
+	Offset int
+	Limit  int
+	// CameraIDs only lists events of these cameras, all cameras if empty
+	CameraIDs []int
+	// From and To only list events within this window, zero values leave
+	// the window open on that side
+	From time.Time
+	To   time.Time
+	// Reasons only keeps events with one of these reasons. Filtering
+	// happens on the client.
+	Reasons []RecordingReason
+	// Read only keeps read (true) or unread (false) events. Filtering
+	// happens on the client.
+	Read *bool
+}
+
+// EventList is a page of events returned by ListEvents
+type EventList struct {
+	Events []Event
+	// Total is the number of events matching the server side filters,
+	// regardless of paging
+	Total int
+}
+
+// ListEvents lists the events matching opts
+func (c *SurveillanceStationClient) ListEvents(opts *ListEventsOptions) (*EventList, error) {
+	return c.ListEventsContext(context.Background(), opts)
+}
+
+// ListEventsContext lists the events matching opts, bound to ctx
+func (c *SurveillanceStationClient) ListEventsContext(ctx context.Context, opts *ListEventsOptions) (*EventList, error) {
+	if opts == nil {
+		opts = &ListEventsOptions{}
+	}
+	params := url.Values{}
+	if opts.Offset > 0 {
+		params.Set("offset", strconv.Itoa(opts.Offset))
+	}
+	if opts.Limit > 0 {
+		params.Set("limit", strconv.Itoa(opts.Limit))
+	}
+	if len(opts.CameraIDs) > 0 {
+		params.Set("cameraIds", joinIDs(opts.CameraIDs))
+	}
+	if !opts.From.IsZero() {
+		params.Set("fromTime", strconv.FormatInt(opts.From.Unix(), 10))
+	}
+	if !opts.To.IsZero() {
+		params.Set("toTime", strconv.FormatInt(opts.To.Unix(), 10))
+	}
+
+	var data struct {
+		Events []Event `json:"events"`
+		Total  int     `json:"total"`
+	}
+	if err := c.callAPI(ctx, "SYNO.SurveillanceStation.Event", "List", 1, 5, params, &data); err != nil {
+		return nil, fmt.Errorf("failed to list events: %w", err)
+	}
+
+	events := data.Events[:0]
+	for _, event := range data.Events {
+		if opts.matches(event) {
+			events = append(events, event)
+		}
+	}
+	return &EventList{Events: events, Total: data.Total}, nil
+}
+
+// matches applies the client side filters of opts to an event
+func (opts *ListEventsOptions) matches(event Event) bool {
+	if opts.Read != nil && event.Read != *opts.Read {
+		return false
+	}
+	if len(opts.Reasons) == 0 {
+		return true
+	}
+	for _, reason := range opts.Reasons {
+		if event.Reason == reason {
+			return true
+		}
+	}
+	return false
+}
+
+// EventRecording returns the recording of an event, failing with
+// ErrNoRecording if the NAS no longer has it
+func (c *SurveillanceStationClient) EventRecording(event Event) (*Recording, error) {
+	return c.EventRecordingContext(context.Background(), event)
+}
+
+// EventRecordingContext returns the recording of an event, bound to ctx
+func (c *SurveillanceStationClient) EventRecordingContext(ctx context.Context, event Event) (*Recording, error) {
+	// An ongoing event has no stop time, leaving the window open ended
+	it := c.IterateRecordings(&ListRecordingsOptions{
+		CameraIDs: []int{event.CameraID},
+		From:      event.Start(),
+		To:        event.Stop(),
+	})
+
+	// Event recordings share the ID of their event, otherwise fall back to
+	// the recording covering the start of the event
+	var covering *Recording
+	for it.Next(ctx) {
+		recording := it.Recording()
+		if recording.ID == event.ID {
+			return &recording, nil
+		}
+		if covering == nil && recording.StartTime <= event.StartTime && (recording.StopTime == 0 || recording.StopTime >= event.StartTime) {
+			covering = &recording
+		}
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	if covering == nil {
+		return nil, fmt.Errorf("%w: event ID %d", ErrNoRecording, event.ID)
+	}
+	return covering, nil
+}
+
+// EventSnapshot returns the JPEG thumbnail of the recording of an event
+func (c *SurveillanceStationClient) EventSnapshot(event Event) ([]byte, error) {
+	return c.EventSnapshotContext(context.Background(), event)
+}
+
+// EventSnapshotContext returns the JPEG thumbnail of the recording of an event, bound to ctx
+func (c *SurveillanceStationClient) EventSnapshotContext(ctx context.Context, event Event) ([]byte, error) {
+	const api, method = "SYNO.SurveillanceStation.Recording", "GetThumbnail"
+
+	recording, err := c.EventRecordingContext(ctx, event)
+	if err != nil {
+		return nil, err
+	}
+
+	params := url.Values{}
+	params.Set("id", strconv.Itoa(recording.ID))
+	endpoint, params, err := c.prepareAPI(ctx, api, method, 1, 6, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get snapshot of event ID %d: %w", event.ID, err)
+	}
+
+	var buf bytes.Buffer
+	err = c.withSession(ctx, func(sid string) error {
+		params.Set("_sid", sid)
+		buf.Reset()
+		_, err := c.writeImage(ctx, endpoint, api, method, params, &buf)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get snapshot of event ID %d: %w", event.ID, err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,93 @@
+package sssg
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestListEventsFilters(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("api") != "SYNO.SurveillanceStation.Event" || query.Get("cameraIds") != "1" || query.Get("fromTime") != "1700000000" {
+			t.Errorf("Unexpected request: %v", query)
+		}
+		w.Write([]byte(`{"success":true,"data":{"total":3,"events":[
+			{"id":1,"cameraId":1,"reason":2,"read":false},
+			{"id":2,"cameraId":1,"reason":9,"read":false},
+			{"id":3,"cameraId":1,"reason":2,"read":true}
+		]}}`))
+	})
+
+	unread := false
+	list, err := client.ListEvents(&ListEventsOptions{
+		CameraIDs: []int{1},
+		From:      time.Unix(1700000000, 0),
+		Reasons:   []RecordingReason{RecordingMotion},
+		Read:      &unread,
+	})
+	if err != nil {
+		t.Fatalf("Failed to list events: %v", err)
+	}
+	if len(list.Events) != 1 || list.Events[0].ID != 1 || list.Total != 3 {
+		t.Errorf("Unexpected events: %+v", list)
+	}
+	if got := list.Events[0].Reason.String(); got != "Motion" {
+		t.Errorf("Unexpected reason: %s", got)
+	}
+}
+
+func TestEventSnapshot(t *testing.T) {
+	jpegData := testJPEG(t)
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("method") {
+		case "List":
+			w.Write([]byte(`{"success":true,"data":{"total":2,"recordings":[
+				{"id":20,"cameraId":1,"startTime":900,"stopTime":950},
+				{"id":21,"cameraId":1,"startTime":950,"stopTime":1100}
+			]}}`))
+		case "GetThumbnail":
+			if got := r.URL.Query().Get("id"); got != "21" {
+				t.Errorf("Unexpected recording ID: %s", got)
+			}
+			w.Header().Set("Content-Type", "image/jpeg")
+			w.Write(jpegData)
+		}
+	})
+
+	event := Event{ID: 5, CameraID: 1, StartTime: 1000, StopTime: 1010}
+	data, err := client.EventSnapshot(event)
+	if err != nil {
+		t.Fatalf("Failed to get event snapshot: %v", err)
+	}
+	if !bytes.Equal(data, jpegData) {
+		t.Errorf("Unexpected snapshot data")
+	}
+
+	_, err = client.EventRecording(Event{ID: 6, CameraID: 1, StartTime: 2000})
+	if !errors.Is(err, ErrNoRecording) {
+		t.Errorf("Expected ErrNoRecording, got %v", err)
+	}
+}
+
+func TestEventRecordingOngoing(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if _, ok := query["toTime"]; ok {
+			t.Errorf("Expected no toTime for an ongoing event, got %s", query.Get("toTime"))
+		}
+		w.Write([]byte(`{"success":true,"data":{"total":1,"recordings":[
+			{"id":31,"cameraId":1,"startTime":900,"stopTime":0}
+		]}}`))
+	})
+
+	recording, err := client.EventRecording(Event{ID: 30, CameraID: 1, StartTime: 1000})
+	if err != nil {
+		t.Fatalf("Failed to get recording of ongoing event: %v", err)
+	}
+	if recording.ID != 31 {
+		t.Errorf("Unexpected recording: %+v", recording)
+	}
+}
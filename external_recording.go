@@ -0,0 +1,77 @@
+package sssg
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// stopRecordingTimeout bounds the stop request RecordFor sends on its own
+// context once the caller's context is gone
+const stopRecordingTimeout = 30 * time.Second
+
+// StartRecording makes a camera record regardless of its schedule until
+// StopRecording is called
+func (c *SurveillanceStationClient) StartRecording(cameraID int) error {
+	return c.StartRecordingContext(context.Background(), cameraID)
+}
+
+// StartRecordingContext makes a camera record regardless of its schedule, bound to ctx
+func (c *SurveillanceStationClient) StartRecordingContext(ctx context.Context, cameraID int) error {
+	return c.externalRecording(ctx, cameraID, "start")
+}
+
+// StopRecording ends a recording started with StartRecording
+func (c *SurveillanceStationClient) StopRecording(cameraID int) error {
+	return c.StopRecordingContext(context.Background(), cameraID)
+}
+
+// StopRecordingContext ends a recording started with StartRecording, bound to ctx
+func (c *SurveillanceStationClient) StopRecordingContext(ctx context.Context, cameraID int) error {
+	return c.externalRecording(ctx, cameraID, "stop")
+}
+
+// RecordFor records a camera for duration. The recording is stopped on a
+// context of its own, so it ends even if ctx is cancelled half way, in
+// which case the context error is returned once the camera was stopped.
+func (c *SurveillanceStationClient) RecordFor(ctx context.Context, cameraID int, duration time.Duration) error {
+	if err := c.StartRecordingContext(ctx, cameraID); err != nil {
+		if ctx.Err() == nil {
+			return err
+		}
+		// The NAS may have started recording before the request was cut off
+		c.stopRecording(cameraID)
+		return err
+	}
+
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+
+	var waitErr error
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+		waitErr = ctx.Err()
+	}
+
+	if err := c.stopRecording(cameraID); err != nil {
+		return err
+	}
+	return waitErr
+}
+
+// stopRecording stops a camera independently of any caller context
+func (c *SurveillanceStationClient) stopRecording(cameraID int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), stopRecordingTimeout)
+	defer cancel()
+	return c.StopRecordingContext(ctx, cameraID)
+}
+
+func (c *SurveillanceStationClient) externalRecording(ctx context.Context, cameraID int, action string) error {
+	params := cameraParams(cameraID)
+	params.Set("action", action)
+	if err := c.callAPI(ctx, "SYNO.SurveillanceStation.ExternalRecording", "Record", 1, 3, params, nil); err != nil {
+		return fmt.Errorf("failed to %s recording on camera ID %d: %w", action, cameraID, err)
+	}
+	return nil
+}
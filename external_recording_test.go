@@ -0,0 +1,46 @@
+package sssg
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRecordForStopsAfterCancel(t *testing.T) {
+	var mu sync.Mutex
+	var actions []string
+	started := make(chan struct{})
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("method") != "Record" || query.Get("cameraId") != "4" {
+			t.Errorf("Unexpected request: %v", query)
+		}
+		mu.Lock()
+		actions = append(actions, query.Get("action"))
+		mu.Unlock()
+		if query.Get("action") == "start" {
+			close(started)
+		}
+		w.Write([]byte(`{"success":true}`))
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-started
+		cancel()
+	}()
+
+	err := client.RecordFor(ctx, 4, time.Hour)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected the cancellation to be reported, got %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(actions) != 2 || actions[0] != "start" || actions[1] != "stop" {
+		t.Errorf("Expected the recording to be started and stopped, got %v", actions)
+	}
+}
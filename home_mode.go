@@ -0,0 +1,224 @@
+package sssg
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// SwitchHomeMode turns Home Mode on or off
+func (c *SurveillanceStationClient) SwitchHomeMode(on bool) error {
+	return c.SwitchHomeModeContext(context.Background(), on)
+}
+
+// SwitchHomeModeContext turns Home Mode on or off, bound to ctx
+func (c *SurveillanceStationClient) SwitchHomeModeContext(ctx context.Context, on bool) error {
+	params := url.Values{}
+	params.Set("on", strconv.FormatBool(on))
+
+	if err := c.callAPI(ctx, "SYNO.SurveillanceStation.HomeMode", "Switch", 1, 1, params, nil); err != nil {
+		return fmt.Errorf("failed to switch home mode: %w", err)
+	}
+	return nil
+}
+
+// EnableHomeModeUntil keeps Home Mode on until the given time, overriding the
+// Home Mode schedule once
+func (c *SurveillanceStationClient) EnableHomeModeUntil(until time.Time) error {
+	return c.EnableHomeModeUntilContext(context.Background(), until)
+}
+
+// EnableHomeModeUntilContext keeps Home Mode on until the given time, bound to ctx
+func (c *SurveillanceStationClient) EnableHomeModeUntilContext(ctx context.Context, until time.Time) error {
+	params := url.Values{}
+	params.Set("onetime_enable_on", "true")
+	params.Set("onetime_enable_time", strconv.FormatInt(until.Unix(), 10))
+	params.Set("onetime_disable_on", "false")
+
+	if err := c.saveHomeMode(ctx, params); err != nil {
+		return fmt.Errorf("failed to enable home mode once: %w", err)
+	}
+	return nil
+}
+
+// DisableHomeModeUntil keeps Home Mode off until the given time, overriding
+// the Home Mode schedule once
+func (c *SurveillanceStationClient) DisableHomeModeUntil(until time.Time) error {
+	return c.DisableHomeModeUntilContext(context.Background(), until)
+}
+
+// DisableHomeModeUntilContext keeps Home Mode off until the given time, bound to ctx
+func (c *SurveillanceStationClient) DisableHomeModeUntilContext(ctx context.Context, until time.Time) error {
+	params := url.Values{}
+	params.Set("onetime_disable_on", "true")
+	params.Set("onetime_disable_time", strconv.FormatInt(until.Unix(), 10))
+	params.Set("onetime_enable_on", "false")
+
+	if err := c.saveHomeMode(ctx, params); err != nil {
+		return fmt.Errorf("failed to disable home mode once: %w", err)
+	}
+	return nil
+}
+
+// ClearHomeModeOneTime cancels any one-time enable or disable window so the
+// Home Mode schedule applies again
+func (c *SurveillanceStationClient) ClearHomeModeOneTime() error {
+	return c.ClearHomeModeOneTimeContext(context.Background())
+}
+
+// ClearHomeModeOneTimeContext cancels any one-time window, bound to ctx
+func (c *SurveillanceStationClient) ClearHomeModeOneTimeContext(ctx context.Context) error {
+	params := url.Values{}
+	params.Set("onetime_enable_on", "false")
+	params.Set("onetime_disable_on", "false")
+
+	if err := c.saveHomeMode(ctx, params); err != nil {
+		return fmt.Errorf("failed to clear home mode one-time window: %w", err)
+	}
+	return nil
+}
+
+// saveHomeMode persists the given Home Mode settings, leaving the others as is
+func (c *SurveillanceStationClient) saveHomeMode(ctx context.Context, params url.Values) error {
+	return c.callAPI(ctx, "SYNO.SurveillanceStation.HomeMode", "Save", 1, 1, params, nil)
+}
+
+// OneTimeEnableUntil returns when the one-time enable window ends, if one is active
+func (h *HomeModeInfo) OneTimeEnableUntil() (time.Time, bool) {
+	if !h.OneTimeEnableOn {
+		return time.Time{}, false
+	}
+	return time.Unix(int64(h.OneTimeEnableTime), 0), true
+}
+
+// OneTimeDisableUntil returns when the one-time disable window ends, if one is active
+func (h *HomeModeInfo) OneTimeDisableUntil() (time.Time, bool) {
+	if !h.OneTimeDisableOn {
+		return time.Time{}, false
+	}
+	return time.Unix(int64(h.OneTimeDisableTime), 0), true
+}
+
+// ErrHomeModeConflict is returned when the Home Mode settings changed on the
+// server since the HomeModeInfo being saved was fetched
+var ErrHomeModeConflict = errors.New("home mode settings changed on the server")
+
+// SaveHomeModeOptions controls how SaveHomeModeSettings persists settings
+type SaveHomeModeOptions struct {
+	// DryRun only computes the changes against the server state
+	DryRun bool
+}
+
+// HomeModeChange is a single setting that differs from the server state,
+// identified by its JSON field name
+type HomeModeChange struct {
+	Field string
+	Old   interface{}
+	New   interface{}
+}
+
+// Fields of HomeModeInfo that reflect server state rather than settings.
+// The Home Mode state itself is changed through SwitchHomeMode.
+var homeModeReadOnlyFields = map[string]bool{
+	"actrules":                true,
+	"cameras":                 true,
+	"io_modules":              true,
+	"last_update_time":        true,
+	"mode_schedule_next_time": true,
+	"on":                      true,
+	"reason":                  true,
+}
+
+// SaveHomeModeSettings persists the settings of info that differ from the
+// current server state, such as schedules, geofence, notifications, stream
+// profile and per-camera custom detection, and returns those differences.
+// If the server state changed since info was fetched, as told by
+// LastUpdateTime, nothing is saved and ErrHomeModeConflict is returned along
+// with the differences, which would otherwise revert the other change.
+func (c *SurveillanceStationClient) SaveHomeModeSettings(info *HomeModeInfo, opts *SaveHomeModeOptions) ([]HomeModeChange, error) {
+	return c.SaveHomeModeSettingsContext(context.Background(), info, opts)
+}
+
+// SaveHomeModeSettingsContext persists the changed Home Mode settings, bound to ctx
+func (c *SurveillanceStationClient) SaveHomeModeSettingsContext(ctx context.Context, info *HomeModeInfo, opts *SaveHomeModeOptions) ([]HomeModeChange, error) {
+	current, err := c.GetHomeModeInfoContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := diffHomeModeInfo(current, info)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compare home mode settings: %w", err)
+	}
+	if info.LastUpdateTime != current.LastUpdateTime {
+		return changes, fmt.Errorf("%w: fetch the settings again and reapply the edits", ErrHomeModeConflict)
+	}
+	if len(changes) == 0 || (opts != nil && opts.DryRun) {
+		return changes, nil
+	}
+
+	params := url.Values{}
+	for _, change := range changes {
+		value, err := formatParam(change.New)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode home mode setting %s: %w", change.Field, err)
+		}
+		params.Set(change.Field, value)
+	}
+	if err := c.saveHomeMode(ctx, params); err != nil {
+		return nil, fmt.Errorf("failed to save home mode settings: %w", err)
+	}
+	return changes, nil
+}
+
+// diffHomeModeInfo compares the writable settings of two HomeModeInfo values
+// through their JSON representation, sorted by field name
+func diffHomeModeInfo(current, updated *HomeModeInfo) ([]HomeModeChange, error) {
+	oldFields, err := toJSONMap(current)
+	if err != nil {
+		return nil, err
+	}
+	newFields, err := toJSONMap(updated)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []HomeModeChange
+	for field, newValue := range newFields {
+		if homeModeReadOnlyFields[field] {
+			continue
+		}
+		if oldValue := oldFields[field]; !reflect.DeepEqual(oldValue, newValue) {
+			changes = append(changes, HomeModeChange{Field: field, Old: oldValue, New: newValue})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Field < changes[j].Field })
+	return changes, nil
+}
+
+// toJSONMap converts v into a generic map using its JSON encoding
+func toJSONMap(v interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]interface{}
+	err = json.Unmarshal(data, &fields)
+	return fields, err
+}
+
+// formatParam encodes a decoded JSON value as a WebAPI parameter: strings
+// are sent as is, everything else in its JSON form
+func formatParam(value interface{}) (string, error) {
+	if s, ok := value.(string); ok {
+		return s, nil
+	}
+	data, err := json.Marshal(value)
+	return string(data), err
+}
@@ -0,0 +1,108 @@
+package sssg
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestSwitchHomeMode(t *testing.T) {
+	var query url.Values
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		w.Write([]byte(`{"success":true}`))
+	})
+
+	if err := client.SwitchHomeMode(true); err != nil {
+		t.Fatalf("Failed to switch home mode: %v", err)
+	}
+	if query.Get("method") != "Switch" || query.Get("on") != "true" {
+		t.Errorf("Unexpected query: %v", query)
+	}
+}
+
+func TestEnableHomeModeUntil(t *testing.T) {
+	var query url.Values
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		w.Write([]byte(`{"success":true}`))
+	})
+
+	until := time.Unix(1741624494, 0)
+	if err := client.EnableHomeModeUntil(until); err != nil {
+		t.Fatalf("Failed to enable home mode once: %v", err)
+	}
+	if query.Get("method") != "Save" || query.Get("onetime_enable_on") != "true" || query.Get("onetime_enable_time") != "1741624494" {
+		t.Errorf("Unexpected query: %v", query)
+	}
+
+	info := HomeModeInfo{OneTimeEnableOn: true, OneTimeEnableTime: 1741624494}
+	if got, ok := info.OneTimeEnableUntil(); !ok || !got.Equal(until) {
+		t.Errorf("Unexpected one-time enable window: %v %v", got, ok)
+	}
+	if _, ok := info.OneTimeDisableUntil(); ok {
+		t.Errorf("Expected no one-time disable window")
+	}
+}
+
+func TestSaveHomeModeSettings(t *testing.T) {
+	var saved url.Values
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("method") {
+		case "GetInfo":
+			w.Write([]byte(`{"success":true,"data":{"cameras":"-1","actrules":"-1","io_modules":"","geo_radius":100,"stream_profile":"1,1,1,1,1,1,0","last_update_time":1}}`))
+		case "Save":
+			saved = r.URL.Query()
+			w.Write([]byte(`{"success":true}`))
+		}
+	})
+
+	info, err := client.GetHomeModeInfo()
+	if err != nil {
+		t.Fatalf("Failed to get home mode info: %v", err)
+	}
+	info.GeoRadius = 250
+
+	changes, err := client.SaveHomeModeSettings(info, &SaveHomeModeOptions{DryRun: true})
+	if err != nil {
+		t.Fatalf("Dry run failed: %v", err)
+	}
+	if len(changes) != 1 || changes[0].Field != "geo_radius" || changes[0].Old != float64(100) || changes[0].New != float64(250) {
+		t.Errorf("Unexpected changes: %+v", changes)
+	}
+	if saved != nil {
+		t.Fatalf("Dry run must not save settings")
+	}
+
+	if _, err := client.SaveHomeModeSettings(info, nil); err != nil {
+		t.Fatalf("Failed to save home mode settings: %v", err)
+	}
+	if saved.Get("geo_radius") != "250" || saved.Has("stream_profile") || saved.Has("last_update_time") {
+		t.Errorf("Unexpected saved settings: %v", saved)
+	}
+}
+
+func TestSaveHomeModeSettingsConflict(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("method") {
+		case "GetInfo":
+			w.Write([]byte(`{"success":true,"data":{"geo_radius":100,"notify_on":false,"last_update_time":2}}`))
+		case "Save":
+			t.Errorf("Expected a stale copy not to be saved")
+		}
+	})
+
+	// A copy fetched before another client turned notifications off
+	stale := &HomeModeInfo{GeoRadius: 250, NotifyOn: true, LastUpdateTime: 1}
+	for _, opts := range []*SaveHomeModeOptions{{DryRun: true}, nil} {
+		changes, err := client.SaveHomeModeSettings(stale, opts)
+		if !errors.Is(err, ErrHomeModeConflict) {
+			t.Errorf("Expected ErrHomeModeConflict, got %v", err)
+		}
+		if len(changes) != 2 {
+			t.Errorf("Expected the conflicting differences, got %+v", changes)
+		}
+	}
+}
@@ -0,0 +1,234 @@
+package sssg
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxFrameSize bounds a single MJPEG frame so a broken stream cannot
+// exhaust memory
+const maxFrameSize = 16 << 20
+
+// Frame is a single JPEG frame of a live stream
+type Frame struct {
+	CameraID int
+	Data     []byte
+	Time     time.Time
+}
+
+// LiveStreamOptions controls how StreamMJPEG reads a live stream
+type LiveStreamOptions struct {
+	// MaxFPS drops frames arriving faster than this rate, zero keeps every frame
+	MaxFPS float64
+	// MinBackoff is the delay before the first reconnect, 1 second by default
+	MinBackoff time.Duration
+	// MaxBackoff caps the doubling reconnect delay, 30 seconds by default
+	MaxBackoff time.Duration
+	// OnError is called with every error that interrupts the stream
+	OnError func(error)
+}
+
+// StreamMJPEG streams the live view of a camera as JPEG frames, using the
+// MJPEG endpoint of Surveillance Station and the client's HTTP transport.
+// Dropped connections are reopened with exponential backoff. The channel is
+// closed when ctx is done, or when the NAS rejects the stream for good, such
+// as with ErrPermissionDenied or ErrAPIUnsupported.
+func (c *SurveillanceStationClient) StreamMJPEG(ctx context.Context, cameraID int, opts *LiveStreamOptions) <-chan Frame {
+	if opts == nil {
+		opts = &LiveStreamOptions{}
+	}
+	minBackoff, maxBackoff := opts.MinBackoff, opts.MaxBackoff
+	if minBackoff <= 0 {
+		minBackoff = time.Second
+	}
+	if maxBackoff <= 0 {
+		maxBackoff = 30 * time.Second
+	}
+	if maxBackoff < minBackoff {
+		maxBackoff = minBackoff
+	}
+	var interval time.Duration
+	if opts.MaxFPS > 0 {
+		interval = time.Duration(float64(time.Second) / opts.MaxFPS)
+	}
+
+	frames := make(chan Frame)
+	go func() {
+		defer close(frames)
+
+		backoff := minBackoff
+		var last time.Time
+		for {
+			err := c.readMJPEG(ctx, cameraID, func(data []byte) bool {
+				backoff = minBackoff
+				now := time.Now()
+				if interval > 0 && !last.IsZero() && now.Sub(last) < interval {
+					return true
+				}
+				last = now
+
+				select {
+				case frames <- Frame{CameraID: cameraID, Data: data, Time: now}:
+					return true
+				case <-ctx.Done():
+					return false
+				}
+			})
+			if ctx.Err() != nil {
+				return
+			}
+			if err == nil {
+				err = io.ErrUnexpectedEOF
+			}
+			err = fmt.Errorf("live stream of camera ID %d interrupted: %w", cameraID, err)
+			if opts.OnError != nil {
+				opts.OnError(err)
+			}
+			if errors.Is(err, ErrPermissionDenied) || errors.Is(err, ErrAPIUnsupported) {
+				return
+			}
+
+			select {
+			case <-time.After(backoff):
+			case <-ctx.Done():
+				return
+			}
+			backoff *= 2
+			if backoff > maxBackoff {
+				backoff = maxBackoff
+			}
+		}
+	}()
+	return frames
+}
+
+// readMJPEG opens the MJPEG stream of a camera and passes every frame to
+// emit until emit returns false or the stream fails
+func (c *SurveillanceStationClient) readMJPEG(ctx context.Context, cameraID int, emit func([]byte) bool) error {
+	const api, method = "SYNO.SurveillanceStation.Stream.VideoStreaming", "Stream"
+
+	params := cameraParams(cameraID)
+	params.Set("format", "mjpeg")
+	endpoint, params, err := c.prepareAPI(ctx, api, method, 1, 1, params)
+	if err != nil {
+		return err
+	}
+
+	var body io.ReadCloser
+	var boundary string
+	err = c.withSession(ctx, func(sid string) error {
+		params.Set("_sid", sid)
+		resp, err := c.get(ctx, endpoint, params)
+		if err != nil {
+			return err
+		}
+
+		mediaType, mediaParams, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+		if !strings.HasPrefix(mediaType, "multipart/") || mediaParams["boundary"] == "" {
+			defer resp.Body.Close()
+			if err := decodeResponse(resp.Body, api, method, nil); err != nil {
+				var apiErr *APIError
+				if errors.As(err, &apiErr) {
+					return err
+				}
+			}
+			return fmt.Errorf("expected a multipart stream, got content type %q", resp.Header.Get("Content-Type"))
+		}
+		body, boundary = resp.Body, mediaParams["boundary"]
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	reader := newMJPEGReader(body, boundary)
+	for {
+		data, err := reader.next()
+		if err != nil {
+			return err
+		}
+		if !emit(data) {
+			return nil
+		}
+	}
+}
+
+// mjpegReader splits a multipart/x-mixed-replace body into its parts. It
+// accepts boundaries with or without the leading dashes that some cameras
+// get wrong, and parts with or without a Content-Length header.
+type mjpegReader struct {
+	r        *bufio.Reader
+	boundary string
+	// atPart is set when the boundary of the next part was already consumed
+	atPart bool
+}
+
+func newMJPEGReader(r io.Reader, boundary string) *mjpegReader {
+	return &mjpegReader{r: bufio.NewReader(r), boundary: strings.TrimLeft(boundary, "-")}
+}
+
+// next returns the body of the next part
+func (m *mjpegReader) next() ([]byte, error) {
+	for !m.atPart {
+		line, err := m.r.ReadSlice('\n')
+		if err != nil && !errors.Is(err, bufio.ErrBufferFull) {
+			return nil, err
+		}
+		m.atPart = m.isBoundary(line)
+	}
+	m.atPart = false
+
+	header, err := textproto.NewReader(m.r).ReadMIMEHeader()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read part header: %w", err)
+	}
+
+	if length := header.Get("Content-Length"); length != "" {
+		n, err := strconv.Atoi(strings.TrimSpace(length))
+		if err != nil || n < 0 || n > maxFrameSize {
+			return nil, fmt.Errorf("invalid part length %q", length)
+		}
+		data := make([]byte, n)
+		if _, err := io.ReadFull(m.r, data); err != nil {
+			return nil, err
+		}
+		return data, nil
+	}
+
+	// Without a length the part runs up to the CRLF before the next boundary
+	var buf bytes.Buffer
+	for {
+		line, err := m.r.ReadBytes('\n')
+		if m.isBoundary(line) {
+			m.atPart = true
+			return bytes.TrimSuffix(bytes.TrimSuffix(buf.Bytes(), []byte("\n")), []byte("\r")), nil
+		}
+		buf.Write(line)
+		if buf.Len() > maxFrameSize {
+			return nil, fmt.Errorf("part exceeds %d bytes", maxFrameSize)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// isBoundary reports whether line is a part boundary or the closing one
+func (m *mjpegReader) isBoundary(line []byte) bool {
+	line = bytes.TrimRight(line, "\r\n")
+	if !bytes.HasPrefix(line, []byte("--")) {
+		return false
+	}
+	line = bytes.TrimLeft(line, "-")
+	return string(line) == m.boundary || string(line) == m.boundary+"--"
+}
@@ -0,0 +1,74 @@
+package sssg
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestStreamMJPEG(t *testing.T) {
+	var connections int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("format"); got != "mjpeg" {
+			t.Errorf("Unexpected format: %s", got)
+		}
+		if atomic.AddInt32(&connections, 1) > 1 {
+			w.Write([]byte(`{"success":false,"error":{"code":105}}`))
+			return
+		}
+		w.Header().Set("Content-Type", "multipart/x-mixed-replace; boundary=--myboundary")
+		w.Write([]byte("--myboundary\r\nContent-Type: image/jpeg\r\nContent-Length: 5\r\n\r\nframe\r\n" +
+			"--myboundary\r\nContent-Type: image/jpeg\r\n\r\nline\none\r\n" +
+			"--myboundary\r\nContent-Type: image/jpeg\r\n\r\nlast\r\n--myboundary--\r\n"))
+	})
+
+	var errs []error
+	frames := client.StreamMJPEG(context.Background(), 3, &LiveStreamOptions{
+		MinBackoff: time.Millisecond,
+		OnError:    func(err error) { errs = append(errs, err) },
+	})
+
+	var got []string
+	for frame := range frames {
+		if frame.CameraID != 3 || frame.Time.IsZero() {
+			t.Errorf("Unexpected frame: %+v", frame)
+		}
+		got = append(got, string(frame.Data))
+	}
+
+	if strings.Join(got, "|") != "frame|line\none|last" {
+		t.Errorf("Unexpected frames: %q", got)
+	}
+	if len(errs) != 2 || !errors.Is(errs[1], ErrPermissionDenied) {
+		t.Errorf("Expected a dropped stream followed by permission denied, got %v", errs)
+	}
+}
+
+func TestStreamMJPEGThrottle(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "multipart/x-mixed-replace; boundary=frame")
+		for i := 0; i < 5; i++ {
+			w.Write([]byte("--frame\r\nContent-Length: 1\r\n\r\nx\r\n"))
+		}
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	frames := client.StreamMJPEG(ctx, 1, &LiveStreamOptions{MaxFPS: 1})
+
+	<-frames
+	select {
+	case <-frames:
+		t.Errorf("Expected frames within a second of the first to be dropped")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	cancel()
+	for range frames {
+	}
+}
@@ -0,0 +1,139 @@
+package sssg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// ErrProfileUnsupported is returned by LiveViewPath.URL for live view URLs
+// that do not let the caller pick a stream profile
+var ErrProfileUnsupported = errors.New("stream profile cannot be selected")
+
+// StreamProtocol selects one of the live view URLs of a camera
+type StreamProtocol int
+
+const (
+	ProtocolRTSP StreamProtocol = iota
+	ProtocolRTSPOverHTTP
+	ProtocolMJPEG
+	ProtocolMulticast
+)
+
+func (p StreamProtocol) String() string {
+	switch p {
+	case ProtocolRTSP:
+		return "RTSP"
+	case ProtocolRTSPOverHTTP:
+		return "RTSP over HTTP"
+	case ProtocolMJPEG:
+		return "MJPEG"
+	case ProtocolMulticast:
+		return "Multicast"
+	}
+	return fmt.Sprintf("StreamProtocol(%d)", int(p))
+}
+
+// LiveViewPath holds the live stream URLs of a camera. URLs carrying a
+// stream key (StmKey) stay valid on their own, while WebAPI URLs without one
+// carry the session id and stop working once that session ends.
+type LiveViewPath struct {
+	CameraID         int    `json:"id"`
+	RTSPPath         string `json:"rtspPath"`
+	RTSPOverHTTPPath string `json:"rtspOverHttpPath"`
+	MJPEGHTTPPath    string `json:"mjpegHttpPath"`
+	MxPEGHTTPPath    string `json:"mxpegHttpPath"`
+	MulticastPath    string `json:"multicstPath"`
+}
+
+// GetLiveViewPath returns the live stream URLs of the given cameras. WebAPI
+// URLs, including RTSP over HTTP, are made absolute and, unless the NAS
+// already put a stream key in them, carry the current session id so they
+// can be handed to ffmpeg or go2rtc as is. Such URLs only live as long as
+// the session: StartKeepAlive keeps it going, but a logout or a re-login
+// after expiry invalidates them, so fetch them again whenever the session
+// changes.
+func (c *SurveillanceStationClient) GetLiveViewPath(ids []int) ([]LiveViewPath, error) {
+	return c.GetLiveViewPathContext(context.Background(), ids)
+}
+
+// GetLiveViewPathContext returns the live stream URLs of the given cameras, bound to ctx
+func (c *SurveillanceStationClient) GetLiveViewPathContext(ctx context.Context, ids []int) ([]LiveViewPath, error) {
+	params := url.Values{}
+	params.Set("idList", joinIDs(ids))
+
+	var paths []LiveViewPath
+	if err := c.callAPI(ctx, "SYNO.SurveillanceStation.Camera", "GetLiveViewPath", 1, 9, params, &paths); err != nil {
+		return nil, fmt.Errorf("failed to get live view path: %w", err)
+	}
+
+	sid := c.sessionID()
+	for i := range paths {
+		p := &paths[i]
+		p.RTSPOverHTTPPath = c.withSessionToken(p.RTSPOverHTTPPath, sid)
+		p.MJPEGHTTPPath = c.withSessionToken(p.MJPEGHTTPPath, sid)
+		p.MxPEGHTTPPath = c.withSessionToken(p.MxPEGHTTPPath, sid)
+	}
+	return paths, nil
+}
+
+// withSessionToken resolves a path relative to BaseURL and adds the session
+// id to WebAPI URLs, whatever their scheme, unless the URL already carries
+// one or a stream key
+func (c *SurveillanceStationClient) withSessionToken(path, sid string) string {
+	if path == "" {
+		return ""
+	}
+	if strings.HasPrefix(path, "/") {
+		path = strings.TrimSuffix(c.BaseURL, "/") + path
+	}
+
+	u, err := url.Parse(path)
+	if err != nil || !strings.HasPrefix(u.Path, "/webapi/") {
+		return path
+	}
+	query := u.Query()
+	if query.Get("_sid") != "" || query.Get("StmKey") != "" || sid == "" {
+		return path
+	}
+	query.Set("_sid", sid)
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+// URL returns the live stream URL for the given protocol with the requested
+// stream profile. Only the VideoStreaming WebAPI, which serves MJPEG, lets
+// the caller pick a profile through its profileType parameter. Other
+// protocols fail with ErrProfileUnsupported; use their LiveViewPath field
+// to get the profile the NAS configured for them.
+func (p LiveViewPath) URL(protocol StreamProtocol, profile ProfileType) (string, error) {
+	var raw string
+	switch protocol {
+	case ProtocolRTSP:
+		raw = p.RTSPPath
+	case ProtocolRTSPOverHTTP:
+		raw = p.RTSPOverHTTPPath
+	case ProtocolMJPEG:
+		raw = p.MJPEGHTTPPath
+	case ProtocolMulticast:
+		raw = p.MulticastPath
+	}
+	if raw == "" {
+		return "", fmt.Errorf("camera ID %d has no %s live view", p.CameraID, protocol)
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", fmt.Errorf("invalid %s live view URL for camera ID %d: %w", protocol, p.CameraID, err)
+	}
+	query := u.Query()
+	if !strings.HasPrefix(u.Path, "/webapi/") || query.Get("api") != "SYNO.SurveillanceStation.Stream.VideoStreaming" {
+		return "", fmt.Errorf("%w: %s live view of camera ID %d", ErrProfileUnsupported, protocol, p.CameraID)
+	}
+	query.Set("profileType", strconv.Itoa(int(profile)))
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
@@ -0,0 +1,209 @@
+package sssg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// ErrPTZUnsupported is returned when a camera lacks the requested PTZ feature
+var ErrPTZUnsupported = errors.New("PTZ feature not supported by camera")
+
+// PTZDirection is a continuous move direction, expressed in the 32 step
+// compass of the PTZ API where 0 is right and 8 is up
+type PTZDirection int
+
+const (
+	PTZRight     PTZDirection = 0
+	PTZUpRight   PTZDirection = 4
+	PTZUp        PTZDirection = 8
+	PTZUpLeft    PTZDirection = 12
+	PTZLeft      PTZDirection = 16
+	PTZDownLeft  PTZDirection = 20
+	PTZDown      PTZDirection = 24
+	PTZDownRight PTZDirection = 28
+)
+
+// PTZControl selects the direction of a zoom, focus or iris adjustment
+type PTZControl string
+
+const (
+	PTZIn  PTZControl = "in"
+	PTZOut PTZControl = "out"
+)
+
+// PTZController drives a single PTZ camera, checking every action against
+// the capabilities reported by the camera
+type PTZController struct {
+	Camera CameraDetail
+	client *SurveillanceStationClient
+}
+
+// PTZ returns a controller for the camera with the given ID, failing with
+// ErrPTZUnsupported if the camera cannot pan, tilt or zoom at all
+func (c *SurveillanceStationClient) PTZ(cameraID int) (*PTZController, error) {
+	return c.PTZContext(context.Background(), cameraID)
+}
+
+// PTZContext returns a controller for the camera with the given ID, bound to ctx
+func (c *SurveillanceStationClient) PTZContext(ctx context.Context, cameraID int) (*PTZController, error) {
+	details, err := c.GetCameraInfoContext(ctx, []int{cameraID}, &CameraInfoOptions{PTZ: true})
+	if err != nil {
+		return nil, err
+	}
+	if len(details) == 0 {
+		return nil, fmt.Errorf("camera ID %d not found", cameraID)
+	}
+
+	caps := details[0].PTZCapabilities
+	if caps.PTZPan == 0 && caps.PTZTilt == 0 && caps.PTZZoom == 0 {
+		return nil, fmt.Errorf("%w: camera ID %d has no PTZ support", ErrPTZUnsupported, cameraID)
+	}
+	return &PTZController{Camera: details[0], client: c}, nil
+}
+
+// Move starts moving the camera in the given direction at speed 1 (slowest)
+// to 5 (fastest) until Stop is called
+func (p *PTZController) Move(direction PTZDirection, speed int) error {
+	return p.MoveContext(context.Background(), direction, speed)
+}
+
+// MoveContext starts moving the camera in the given direction, bound to ctx
+func (p *PTZController) MoveContext(ctx context.Context, direction PTZDirection, speed int) error {
+	if speed < 1 || speed > 5 {
+		return fmt.Errorf("PTZ speed must be between 1 and 5, got %d", speed)
+	}
+	horizontal := direction != PTZUp && direction != PTZDown
+	vertical := direction != PTZLeft && direction != PTZRight
+	if horizontal {
+		if err := p.require("pan", p.Camera.PTZPan); err != nil {
+			return err
+		}
+	}
+	if vertical {
+		if err := p.require("tilt", p.Camera.PTZTilt); err != nil {
+			return err
+		}
+	}
+
+	params := url.Values{}
+	params.Set("direction", strconv.Itoa(int(direction)))
+	params.Set("speed", strconv.Itoa(speed))
+	params.Set("moveType", "Start")
+	return p.call(ctx, "Move", params)
+}
+
+// Zoom starts zooming in or out until Stop is called
+func (p *PTZController) Zoom(control PTZControl) error {
+	return p.ZoomContext(context.Background(), control)
+}
+
+// ZoomContext starts zooming in or out, bound to ctx
+func (p *PTZController) ZoomContext(ctx context.Context, control PTZControl) error {
+	if err := p.require("zoom", p.Camera.PTZZoom); err != nil {
+		return err
+	}
+	return p.control(ctx, "Zoom", control, "Start")
+}
+
+// Focus starts adjusting the focus nearer (in) or farther (out) until Stop
+// is called
+func (p *PTZController) Focus(control PTZControl) error {
+	return p.FocusContext(context.Background(), control)
+}
+
+// FocusContext starts adjusting the focus, bound to ctx
+func (p *PTZController) FocusContext(ctx context.Context, control PTZControl) error {
+	if err := p.require("focus", p.Camera.PTZFocus); err != nil {
+		return err
+	}
+	return p.control(ctx, "Focus", control, "Start")
+}
+
+// Iris starts opening (in) or closing (out) the iris until Stop is called
+func (p *PTZController) Iris(control PTZControl) error {
+	return p.IrisContext(context.Background(), control)
+}
+
+// IrisContext starts opening or closing the iris, bound to ctx
+func (p *PTZController) IrisContext(ctx context.Context, control PTZControl) error {
+	if err := p.require("iris", p.Camera.PTZIris); err != nil {
+		return err
+	}
+	return p.control(ctx, "Iris", control, "Start")
+}
+
+// AutoFocus lets the camera focus automatically
+func (p *PTZController) AutoFocus() error {
+	return p.AutoFocusContext(context.Background())
+}
+
+// AutoFocusContext lets the camera focus automatically, bound to ctx
+func (p *PTZController) AutoFocusContext(ctx context.Context) error {
+	if err := p.require("auto focus", p.Camera.PTZAutoFocus); err != nil {
+		return err
+	}
+	return p.call(ctx, "AutoFocus", url.Values{})
+}
+
+// Stop stops every continuous move, zoom, focus and iris adjustment the
+// camera supports
+func (p *PTZController) Stop() error {
+	return p.StopContext(context.Background())
+}
+
+// StopContext stops every continuous adjustment of the camera, bound to ctx
+func (p *PTZController) StopContext(ctx context.Context) error {
+	if p.Camera.PTZPan != 0 || p.Camera.PTZTilt != 0 {
+		params := url.Values{}
+		params.Set("moveType", "Stop")
+		if err := p.call(ctx, "Move", params); err != nil {
+			return err
+		}
+	}
+	for _, action := range []struct {
+		method    string
+		supported int
+	}{
+		{"Zoom", p.Camera.PTZZoom},
+		{"Focus", p.Camera.PTZFocus},
+		{"Iris", p.Camera.PTZIris},
+	} {
+		if action.supported == 0 {
+			continue
+		}
+		if err := p.control(ctx, action.method, PTZIn, "Stop"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// require fails with ErrPTZUnsupported when capability is zero
+func (p *PTZController) require(feature string, capability int) error {
+	if capability == 0 {
+		return fmt.Errorf("%w: camera ID %d cannot %s", ErrPTZUnsupported, p.Camera.ID, feature)
+	}
+	return nil
+}
+
+func (p *PTZController) control(ctx context.Context, method string, control PTZControl, moveType string) error {
+	if control != PTZIn && control != PTZOut {
+		return fmt.Errorf("invalid PTZ control %q", control)
+	}
+	params := url.Values{}
+	params.Set("control", string(control))
+	params.Set("moveType", moveType)
+	return p.call(ctx, method, params)
+}
+
+func (p *PTZController) call(ctx context.Context, method string, params url.Values) error {
+	params.Set("cameraId", strconv.Itoa(p.Camera.ID))
+	if err := p.client.callAPI(ctx, "SYNO.SurveillanceStation.PTZ", method, 1, 5, params, nil); err != nil {
+		return fmt.Errorf("failed to %s camera ID %d: %w", strings.ToLower(method), p.Camera.ID, err)
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package sssg
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// Preset is a stored PTZ position of a camera
+type Preset struct {
+	ID       int    `json:"id"`
+	CameraID int    `json:"cameraId"`
+	Position int    `json:"position"`
+	Name     string `json:"name"`
+	Speed    int    `json:"speed"`
+	Type     int    `json:"type"`
+}
+
+// Patrol is a stored tour over the presets of a camera
+type Patrol struct {
+	ID       int    `json:"id"`
+	CameraID int    `json:"cameraId"`
+	Name     string `json:"name"`
+}
+
+// ListPresets lists the PTZ presets of the camera
+func (p *PTZController) ListPresets() ([]Preset, error) {
+	return p.ListPresetsContext(context.Background())
+}
+
+// ListPresetsContext lists the PTZ presets of the camera, bound to ctx
+func (p *PTZController) ListPresetsContext(ctx context.Context) ([]Preset, error) {
+	var data struct {
+		Presets []Preset `json:"presets"`
+	}
+	if err := p.callPreset(ctx, "SYNO.SurveillanceStation.PTZ.Preset", "Enum", url.Values{}, &data); err != nil {
+		return nil, fmt.Errorf("failed to list presets for camera ID %d: %w", p.Camera.ID, err)
+	}
+
+	for i := range data.Presets {
+		data.Presets[i].CameraID = p.Camera.ID
+	}
+	return data.Presets, nil
+}
+
+// GoPreset moves the camera to one of its presets
+func (p *PTZController) GoPreset(presetID int) error {
+	return p.GoPresetContext(context.Background(), presetID)
+}
+
+// GoPresetContext moves the camera to one of its presets, bound to ctx
+func (p *PTZController) GoPresetContext(ctx context.Context, presetID int) error {
+	params := url.Values{}
+	params.Set("presetId", strconv.Itoa(presetID))
+
+	if err := p.callPreset(ctx, "SYNO.SurveillanceStation.PTZ.Preset", "Execute", params, nil); err != nil {
+		return fmt.Errorf("failed to go to preset %d of camera ID %d: %w", presetID, p.Camera.ID, err)
+	}
+	return nil
+}
+
+// SetPreset stores the current position of the camera as the preset at
+// position, moving there at speed 1 (slowest) to 5 (fastest) when recalled
+func (p *PTZController) SetPreset(position int, name string, speed int) error {
+	return p.SetPresetContext(context.Background(), position, name, speed)
+}
+
+// SetPresetContext stores the current position of the camera as a preset, bound to ctx
+func (p *PTZController) SetPresetContext(ctx context.Context, position int, name string, speed int) error {
+	if speed < 1 || speed > 5 {
+		return fmt.Errorf("PTZ speed must be between 1 and 5, got %d", speed)
+	}
+	params := url.Values{}
+	params.Set("position", strconv.Itoa(position))
+	params.Set("name", name)
+	params.Set("speed", strconv.Itoa(speed))
+
+	if err := p.callPreset(ctx, "SYNO.SurveillanceStation.PTZ.Preset", "SetPreset", params, nil); err != nil {
+		return fmt.Errorf("failed to set preset %d of camera ID %d: %w", position, p.Camera.ID, err)
+	}
+	return nil
+}
+
+// DelPreset deletes the preset at position
+func (p *PTZController) DelPreset(position int) error {
+	return p.DelPresetContext(context.Background(), position)
+}
+
+// DelPresetContext deletes the preset at position, bound to ctx
+func (p *PTZController) DelPresetContext(ctx context.Context, position int) error {
+	params := url.Values{}
+	params.Set("position", strconv.Itoa(position))
+
+	if err := p.callPreset(ctx, "SYNO.SurveillanceStation.PTZ.Preset", "DelPreset", params, nil); err != nil {
+		return fmt.Errorf("failed to delete preset %d of camera ID %d: %w", position, p.Camera.ID, err)
+	}
+	return nil
+}
+
+// ListPatrols lists the PTZ patrols of the camera
+func (p *PTZController) ListPatrols() ([]Patrol, error) {
+	return p.ListPatrolsContext(context.Background())
+}
+
+// ListPatrolsContext lists the PTZ patrols of the camera, bound to ctx
+func (p *PTZController) ListPatrolsContext(ctx context.Context) ([]Patrol, error) {
+	var data struct {
+		Patrols []Patrol `json:"patrols"`
+	}
+	if err := p.callPreset(ctx, "SYNO.SurveillanceStation.PTZ.Patrol", "Enum", url.Values{}, &data); err != nil {
+		return nil, fmt.Errorf("failed to list patrols for camera ID %d: %w", p.Camera.ID, err)
+	}
+
+	for i := range data.Patrols {
+		data.Patrols[i].CameraID = p.Camera.ID
+	}
+	return data.Patrols, nil
+}
+
+// RunPatrol starts one of the patrols of the camera
+func (p *PTZController) RunPatrol(patrolID int) error {
+	return p.RunPatrolContext(context.Background(), patrolID)
+}
+
+// RunPatrolContext starts one of the patrols of the camera, bound to ctx
+func (p *PTZController) RunPatrolContext(ctx context.Context, patrolID int) error {
+	params := url.Values{}
+	params.Set("patrolId", strconv.Itoa(patrolID))
+
+	if err := p.callPreset(ctx, "SYNO.SurveillanceStation.PTZ.Patrol", "Run", params, nil); err != nil {
+		return fmt.Errorf("failed to run patrol %d of camera ID %d: %w", patrolID, p.Camera.ID, err)
+	}
+	return nil
+}
+
+// callPreset invokes method of the preset or patrol api for the camera
+func (p *PTZController) callPreset(ctx context.Context, api, method string, params url.Values, data interface{}) error {
+	params.Set("cameraId", strconv.Itoa(p.Camera.ID))
+	return p.client.callAPI(ctx, api, method, 1, 1, params, data)
+}
@@ -0,0 +1,71 @@
+package sssg
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestPresetsAndPatrols(t *testing.T) {
+	var last url.Values
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("api") == "SYNO.SurveillanceStation.Camera" {
+			w.Write([]byte(`{"success":true,"data":{"cameras":[{"id":7,"ptzPan":1,"ptzTilt":1}]}}`))
+			return
+		}
+		last = r.URL.Query()
+		switch last.Get("api") + "." + last.Get("method") {
+		case "SYNO.SurveillanceStation.PTZ.Preset.Enum":
+			w.Write([]byte(`{"success":true,"data":{"total":2,"presets":[{"id":1,"position":1,"name":"Gate","speed":3},{"id":2,"position":2,"name":"Porch","speed":5}]}}`))
+		case "SYNO.SurveillanceStation.PTZ.Patrol.Enum":
+			w.Write([]byte(`{"success":true,"data":{"total":1,"patrols":[{"id":4,"name":"Night round"}]}}`))
+		default:
+			w.Write([]byte(`{"success":true}`))
+		}
+	})
+
+	ptz, err := client.PTZ(7)
+	if err != nil {
+		t.Fatalf("Failed to get PTZ controller: %v", err)
+	}
+
+	presets, err := ptz.ListPresets()
+	if err != nil {
+		t.Fatalf("Failed to list presets: %v", err)
+	}
+	if len(presets) != 2 || presets[1].Name != "Porch" || presets[1].CameraID != 7 {
+		t.Errorf("Unexpected presets: %+v", presets)
+	}
+
+	if err := ptz.GoPreset(presets[0].ID); err != nil {
+		t.Fatalf("Failed to go to preset: %v", err)
+	}
+	if last.Get("method") != "Execute" || last.Get("cameraId") != "7" || last.Get("presetId") != "1" {
+		t.Errorf("Unexpected go preset request: %v", last)
+	}
+
+	if err := ptz.SetPreset(3, "Driveway", 4); err != nil {
+		t.Fatalf("Failed to set preset: %v", err)
+	}
+	if last.Get("method") != "SetPreset" || last.Get("position") != "3" || last.Get("name") != "Driveway" || last.Get("speed") != "4" {
+		t.Errorf("Unexpected set preset request: %v", last)
+	}
+	if err := ptz.SetPreset(3, "Driveway", 0); err == nil {
+		t.Errorf("Expected an error for an invalid speed")
+	}
+
+	patrols, err := ptz.ListPatrols()
+	if err != nil {
+		t.Fatalf("Failed to list patrols: %v", err)
+	}
+	if len(patrols) != 1 || patrols[0].Name != "Night round" || patrols[0].CameraID != 7 {
+		t.Errorf("Unexpected patrols: %+v", patrols)
+	}
+
+	if err := ptz.RunPatrol(patrols[0].ID); err != nil {
+		t.Fatalf("Failed to run patrol: %v", err)
+	}
+	if last.Get("api") != "SYNO.SurveillanceStation.PTZ.Patrol" || last.Get("method") != "Run" || last.Get("patrolId") != "4" {
+		t.Errorf("Unexpected run patrol request: %v", last)
+	}
+}
@@ -0,0 +1,82 @@
+package sssg
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+// newPTZTestClient serves a camera with the given PTZ capabilities and
+// records the PTZ requests
+func newPTZTestClient(t *testing.T, capabilities string, requests *[]url.Values) *SurveillanceStationClient {
+	return newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("api") == "SYNO.SurveillanceStation.Camera" {
+			w.Write([]byte(`{"success":true,"data":{"cameras":[{"id":7,` + capabilities + `}]}}`))
+			return
+		}
+		*requests = append(*requests, query)
+		w.Write([]byte(`{"success":true}`))
+	})
+}
+
+func TestPTZMoveAndStop(t *testing.T) {
+	var requests []url.Values
+	client := newPTZTestClient(t, `"ptzPan":1,"ptzTilt":1,"ptzZoom":1`, &requests)
+
+	ptz, err := client.PTZ(7)
+	if err != nil {
+		t.Fatalf("Failed to get PTZ controller: %v", err)
+	}
+	if err := ptz.MoveContext(context.Background(), PTZUpLeft, 3); err != nil {
+		t.Fatalf("Failed to move: %v", err)
+	}
+	if err := ptz.Stop(); err != nil {
+		t.Fatalf("Failed to stop: %v", err)
+	}
+
+	if len(requests) != 3 {
+		t.Fatalf("Expected move, stop and zoom stop requests, got %v", requests)
+	}
+	move := requests[0]
+	if move.Get("method") != "Move" || move.Get("cameraId") != "7" || move.Get("direction") != "12" || move.Get("speed") != "3" || move.Get("moveType") != "Start" {
+		t.Errorf("Unexpected move request: %v", move)
+	}
+	if requests[1].Get("moveType") != "Stop" || requests[2].Get("method") != "Zoom" {
+		t.Errorf("Unexpected stop requests: %v", requests[1:])
+	}
+
+	if err := ptz.Focus(PTZIn); !errors.Is(err, ErrPTZUnsupported) {
+		t.Errorf("Expected ErrPTZUnsupported for focus, got: %v", err)
+	}
+	if err := ptz.Move(PTZUp, 9); err == nil {
+		t.Errorf("Expected an error for an invalid speed")
+	}
+}
+
+func TestPTZUnsupportedCamera(t *testing.T) {
+	var requests []url.Values
+	client := newPTZTestClient(t, `"ptzPan":0`, &requests)
+
+	if _, err := client.PTZ(7); !errors.Is(err, ErrPTZUnsupported) {
+		t.Errorf("Expected ErrPTZUnsupported, got: %v", err)
+	}
+}
+
+func TestPTZTiltOnlyCamera(t *testing.T) {
+	var requests []url.Values
+	client := newPTZTestClient(t, `"ptzTilt":1`, &requests)
+
+	ptz, err := client.PTZ(7)
+	if err != nil {
+		t.Fatalf("Failed to get PTZ controller: %v", err)
+	}
+	if err := ptz.Move(PTZDown, 1); err != nil {
+		t.Errorf("Expected tilting to work: %v", err)
+	}
+	if err := ptz.Move(PTZDownLeft, 1); !errors.Is(err, ErrPTZUnsupported) {
+		t.Errorf("Expected ErrPTZUnsupported for a diagonal move, got: %v", err)
+	}
+}
@@ -0,0 +1,249 @@
+package sssg
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// RecordingReason is what triggered a recording or an event. Recordings and
+// events share these codes.
+type RecordingReason int
+
+const (
+	RecordingReasonNone RecordingReason = iota
+	RecordingContinuous
+	RecordingMotion
+	// RecordingAlarm is triggered by a digital input of the camera
+	RecordingAlarm
+	RecordingCustom
+	RecordingManual
+	RecordingExternal
+	RecordingActionRule
+	RecordingAudio
+	RecordingTampering
+)
+
+func (r RecordingReason) String() string {
+	switch r {
+	case RecordingReasonNone:
+		return "None"
+	case RecordingContinuous:
+		return "Continuous"
+	case RecordingMotion:
+		return "Motion"
+	case RecordingAlarm:
+		return "Alarm"
+	case RecordingCustom:
+		return "Custom"
+	case RecordingManual:
+		return "Manual"
+	case RecordingExternal:
+		return "External"
+	case RecordingActionRule:
+		return "Action rule"
+	case RecordingAudio:
+		return "Audio"
+	case RecordingTampering:
+		return "Tampering"
+	}
+	return fmt.Sprintf("RecordingReason(%d)", int(r))
+}
+
+// Recording is a single recorded clip of a camera
+type Recording struct {
+	ID         int             `json:"id"`
+	CameraID   int             `json:"cameraId"`
+	CameraName string          `json:"camera_name"`
+	DsID       int             `json:"dsId"`
+	FilePath   string          `json:"filePath"`
+	FrameCount int             `json:"frameCount"`
+	StartTime  int64           `json:"startTime"`
+	StopTime   int64           `json:"stopTime"`
+	Reason     RecordingReason `json:"reason"`
+	Status     int             `json:"status"`
+	Locked     bool            `json:"locked"`
+	// Size is the size of the clip in MB
+	Size float64 `json:"eventSize"`
+}
+
+// Start returns the start time of the recording
+func (r Recording) Start() time.Time {
+	return time.Unix(r.StartTime, 0)
+}
+
+// Stop returns the stop time of the recording
+func (r Recording) Stop() time.Time {
+	return time.Unix(r.StopTime, 0)
+}
+
+// Duration returns the length of the recording
+func (r Recording) Duration() time.Duration {
+	return time.Duration(r.StopTime-r.StartTime) * time.Second
+}
+
+// ListRecordingsOptions narrows down and pages the recordings returned by
+// ListRecordings
+type ListRecordingsOptions struct {
+	Offset int
+	Limit  int
+	// CameraIDs only lists recordings of these cameras, all cameras if empty
+	CameraIDs []int
+	// From and To only list recordings overlapping this window, zero values
+	// leave the window open on that side
+	From time.Time
+	To   time.Time
+	// Reasons only keeps recordings with one of these reasons. Filtering
+	// happens on the client.
+	Reasons []RecordingReason
+	// Locked only keeps locked (true) or unlocked (false) recordings
+	Locked *bool
+}
+
+// RecordingList is a page of recordings returned by ListRecordings
+type RecordingList struct {
+	Recordings []Recording
+	// Total is the number of recordings matching the server side filters,
+	// regardless of paging
+	Total int
+}
+
+// ListRecordings lists the recordings matching opts
+func (c *SurveillanceStationClient) ListRecordings(opts *ListRecordingsOptions) (*RecordingList, error) {
+	return c.ListRecordingsContext(context.Background(), opts)
+}
+
+// ListRecordingsContext lists the recordings matching opts, bound to ctx
+func (c *SurveillanceStationClient) ListRecordingsContext(ctx context.Context, opts *ListRecordingsOptions) (*RecordingList, error) {
+	list, _, err := c.listRecordings(ctx, opts)
+	return list, err
+}
+
+// listRecordings lists the recordings matching opts and also returns how
+// many recordings the server sent before client side filtering
+func (c *SurveillanceStationClient) listRecordings(ctx context.Context, opts *ListRecordingsOptions) (*RecordingList, int, error) {
+	if opts == nil {
+		opts = &ListRecordingsOptions{}
+	}
+	params := url.Values{}
+	if opts.Offset > 0 {
+		params.Set("offset", strconv.Itoa(opts.Offset))
+	}
+	if opts.Limit > 0 {
+		params.Set("limit", strconv.Itoa(opts.Limit))
+	}
+	if len(opts.CameraIDs) > 0 {
+		params.Set("cameraIds", joinIDs(opts.CameraIDs))
+	}
+	if !opts.From.IsZero() {
+		params.Set("fromTime", strconv.FormatInt(opts.From.Unix(), 10))
+	}
+	if !opts.To.IsZero() {
+		params.Set("toTime", strconv.FormatInt(opts.To.Unix(), 10))
+	}
+	if opts.Locked != nil && *opts.Locked {
+		params.Set("locked", "1")
+	}
+
+	var data struct {
+		Recordings []Recording `json:"recordings"`
+		Total      int         `json:"total"`
+	}
+	if err := c.callAPI(ctx, "SYNO.SurveillanceStation.Recording", "List", 1, 6, params, &data); err != nil {
+		return nil, 0, fmt.Errorf("failed to list recordings: %w", err)
+	}
+
+	received := len(data.Recordings)
+	recordings := data.Recordings[:0]
+	for _, recording := range data.Recordings {
+		if opts.matches(recording) {
+			recordings = append(recordings, recording)
+		}
+	}
+	return &RecordingList{Recordings: recordings, Total: data.Total}, received, nil
+}
+
+// matches applies the client side filters of opts to a recording
+func (opts *ListRecordingsOptions) matches(recording Recording) bool {
+	if opts.Locked != nil && recording.Locked != *opts.Locked {
+		return false
+	}
+	if len(opts.Reasons) == 0 {
+		return true
+	}
+	for _, reason := range opts.Reasons {
+		if recording.Reason == reason {
+			return true
+		}
+	}
+	return false
+}
+
+// RecordingIterator walks all pages of recordings matching a set of
+// options, fetching the next page when the current one is used up:
+//
+//	it := client.IterateRecordings(opts)
+//	for it.Next(ctx) {
+//		recording := it.Recording()
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type RecordingIterator struct {
+	client  *SurveillanceStationClient
+	opts    ListRecordingsOptions
+	page    []Recording
+	current Recording
+	done    bool
+	err     error
+}
+
+// IterateRecordings returns an iterator over every recording matching
+// opts, starting at opts.Offset and fetching opts.Limit recordings per
+// page, 100 by default
+func (c *SurveillanceStationClient) IterateRecordings(opts *ListRecordingsOptions) *RecordingIterator {
+	it := &RecordingIterator{client: c}
+	if opts != nil {
+		it.opts = *opts
+	}
+	if it.opts.Limit <= 0 {
+		it.opts.Limit = 100
+	}
+	return it
+}
+
+// Next advances to the next recording, reporting false once all pages are
+// read or a page failed to load. Pages shorter than opts.Limit do not end
+// the iteration, since the NAS may cap the page size below it.
+func (it *RecordingIterator) Next(ctx context.Context) bool {
+	for len(it.page) == 0 {
+		if it.done || it.err != nil {
+			return false
+		}
+
+		list, received, err := it.client.listRecordings(ctx, &it.opts)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		it.opts.Offset += received
+		it.page = list.Recordings
+		it.done = received == 0 || it.opts.Offset >= list.Total
+	}
+
+	it.current = it.page[0]
+	it.page = it.page[1:]
+	return true
+}
+
+// Recording returns the recording Next advanced to
+func (it *RecordingIterator) Recording() Recording {
+	return it.current
+}
+
+// Err returns the error that stopped the iteration, if any
+func (it *RecordingIterator) Err() error {
+	return it.err
+}
@@ -0,0 +1,415 @@
+package sssg
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DownloadOptions controls a recording download
+type DownloadOptions struct {
+	// Progress is called after every read with the bytes received so far and
+	// the total size, -1 if the NAS did not announce it
+	Progress func(received, total int64)
+	// Hash receives every byte handed to the reader, so a checksum of the
+	// download is available once it is read to the end
+	Hash hash.Hash
+	// MaxRetries is the number of times a dropped download is resumed with a
+	// Range request, 3 by default
+	MaxRetries int
+	// PollInterval is how often DownloadRange checks whether the NAS has
+	// finished exporting, 1 second by default
+	PollInterval time.Duration
+	// MaxExportWait bounds how long DownloadRange waits for the export,
+	// 30 minutes by default
+	MaxExportWait time.Duration
+}
+
+// ErrExportFailed is returned when the NAS fails or stalls while exporting
+// a range for DownloadRange
+var ErrExportFailed = errors.New("range export failed")
+
+// DownloadRecording downloads a recording as MP4. The returned reader
+// resumes where it left off when the connection drops, and must be closed.
+func (c *SurveillanceStationClient) DownloadRecording(recording Recording, opts *DownloadOptions) (io.ReadCloser, error) {
+	return c.DownloadRecordingContext(context.Background(), recording, opts)
+}
+
+// DownloadRecordingContext downloads a recording as MP4, bound to ctx
+func (c *SurveillanceStationClient) DownloadRecordingContext(ctx context.Context, recording Recording, opts *DownloadOptions) (io.ReadCloser, error) {
+	params := url.Values{}
+	params.Set("id", strconv.Itoa(recording.ID))
+
+	r, err := c.download(ctx, "SYNO.SurveillanceStation.Recording", "Download", params, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download recording ID %d: %w", recording.ID, err)
+	}
+	return r, nil
+}
+
+// DownloadRange exports the footage of a camera between from and to as a
+// single MP4 and downloads it. The export runs on the NAS first, which may
+// take a while for long ranges; ctx and DownloadOptions.MaxExportWait bound
+// the wait. A failed or stalled export returns ErrExportFailed.
+func (c *SurveillanceStationClient) DownloadRange(cameraID int, from, to time.Time, opts *DownloadOptions) (io.ReadCloser, error) {
+	return c.DownloadRangeContext(context.Background(), cameraID, from, to, opts)
+}
+
+// DownloadRangeContext exports and downloads the footage of a camera between
+// from and to, bound to ctx
+func (c *SurveillanceStationClient) DownloadRangeContext(ctx context.Context, cameraID int, from, to time.Time, opts *DownloadOptions) (io.ReadCloser, error) {
+	const api = "SYNO.SurveillanceStation.Recording"
+	if opts == nil {
+		opts = &DownloadOptions{}
+	}
+	interval := opts.PollInterval
+	if interval <= 0 {
+		interval = time.Second
+	}
+	maxWait := opts.MaxExportWait
+	if maxWait <= 0 {
+		maxWait = 30 * time.Minute
+	}
+
+	fileName := fmt.Sprintf("camera-%d-%d-%d", cameraID, from.Unix(), to.Unix())
+	params := url.Values{}
+	params.Set("camId", strconv.Itoa(cameraID))
+	params.Set("fromTime", strconv.FormatInt(from.Unix(), 10))
+	params.Set("toTime", strconv.FormatInt(to.Unix(), 10))
+	params.Set("fileName", fileName)
+
+	var export struct {
+		DlID int `json:"dlid"`
+	}
+	if err := c.callAPI(ctx, api, "RangeExport", 1, 6, params, &export); err != nil {
+		return nil, fmt.Errorf("failed to export range of camera ID %d: %w", cameraID, err)
+	}
+	dlid := strconv.Itoa(export.DlID)
+
+	deadline := time.NewTimer(maxWait)
+	defer deadline.Stop()
+	for {
+		params := url.Values{}
+		params.Set("dlid", dlid)
+		var progress struct {
+			Progress int    `json:"progress"`
+			FileExt  string `json:"fileExt"`
+			Error    int    `json:"error"`
+		}
+		if err := c.callAPI(ctx, api, "GetRangeExportProgress", 1, 6, params, &progress); err != nil {
+			return nil, fmt.Errorf("failed to export range of camera ID %d: %w", cameraID, err)
+		}
+		if progress.Progress < 0 || progress.Error != 0 {
+			return nil, fmt.Errorf("%w: camera ID %d reported progress %d, error %d", ErrExportFailed, cameraID, progress.Progress, progress.Error)
+		}
+		if progress.Progress >= 100 {
+			if progress.FileExt != "" {
+				fileName += "." + progress.FileExt
+			}
+			break
+		}
+
+		select {
+		case <-time.After(interval):
+		case <-deadline.C:
+			return nil, fmt.Errorf("%w: camera ID %d still at %d%% after %s", ErrExportFailed, cameraID, progress.Progress, maxWait)
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	params = url.Values{}
+	params.Set("dlid", dlid)
+	params.Set("filename", fileName)
+	r, err := c.download(ctx, api, "OnRangeExportDone", params, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download range of camera ID %d: %w", cameraID, err)
+	}
+	return r, nil
+}
+
+// download opens a resumable download of method of api
+func (c *SurveillanceStationClient) download(ctx context.Context, api, method string, params url.Values, opts *DownloadOptions) (io.ReadCloser, error) {
+	if opts == nil {
+		opts = &DownloadOptions{}
+	}
+	endpoint, params, err := c.prepareAPI(ctx, api, method, 1, 6, params)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &downloadReader{
+		ctx:     ctx,
+		opts:    opts,
+		total:   -1,
+		retries: opts.MaxRetries,
+		open: func(ctx context.Context, offset int64) (*http.Response, error) {
+			var resp *http.Response
+			err := c.withSession(ctx, func(sid string) error {
+				params.Set("_sid", sid)
+				var err error
+				resp, err = c.getRange(ctx, endpoint, params, offset, api, method)
+				return err
+			})
+			return resp, err
+		},
+	}
+	if r.retries <= 0 {
+		r.retries = 3
+	}
+
+	// Open right away so errors surface here instead of on the first Read
+	if err := r.connect(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// getRange requests endpoint starting at byte offset. JSON bodies are
+// decoded into an *APIError.
+func (c *SurveillanceStationClient) getRange(ctx context.Context, endpoint string, params url.Values, offset int64, api, method string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if mediaType == "application/json" || mediaType == "text/plain" {
+		defer resp.Body.Close()
+		if err := decodeResponse(resp.Body, api, method, nil); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("unexpected %s response", mediaType)
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusPartialContent {
+		resp.Body.Close()
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return resp, nil
+}
+
+// downloadReader reads a download, reopening it at the current offset when
+// the connection drops before the announced size was received
+type downloadReader struct {
+	ctx     context.Context
+	opts    *DownloadOptions
+	open    func(ctx context.Context, offset int64) (*http.Response, error)
+	body    io.ReadCloser
+	offset  int64
+	total   int64
+	retries int
+	closed  bool
+}
+
+// connect opens the download at the current offset
+func (r *downloadReader) connect() error {
+	resp, err := r.open(r.ctx, r.offset)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode == http.StatusPartialContent {
+		// Content-Range: bytes <first>-<last>/<total>
+		if i := strings.LastIndex(resp.Header.Get("Content-Range"), "/"); i >= 0 {
+			if total, err := strconv.ParseInt(resp.Header.Get("Content-Range")[i+1:], 10, 64); err == nil {
+				r.total = total
+			}
+		}
+	} else {
+		if resp.ContentLength >= 0 {
+			r.total = resp.ContentLength
+		}
+		// The NAS ignored the Range header, so skip what was already read
+		if r.offset > 0 {
+			if _, err := io.CopyN(io.Discard, resp.Body, r.offset); err != nil {
+				resp.Body.Close()
+				return err
+			}
+		}
+	}
+	r.body = resp.Body
+	return nil
+}
+
+func (r *downloadReader) Read(p []byte) (int, error) {
+	if r.closed {
+		return 0, errors.New("read on closed download")
+	}
+	for {
+		if r.body == nil {
+			if err := r.connect(); err != nil {
+				return 0, err
+			}
+		}
+
+		n, err := r.body.Read(p)
+		if n > 0 {
+			r.offset += int64(n)
+			if r.opts.Hash != nil {
+				r.opts.Hash.Write(p[:n])
+			}
+			if r.opts.Progress != nil {
+				r.opts.Progress(r.offset, r.total)
+			}
+		}
+
+		truncated := errors.Is(err, io.EOF) && r.total >= 0 && r.offset < r.total
+		dropped := err != nil && !errors.Is(err, io.EOF)
+		if (!truncated && !dropped) || r.retries == 0 || r.ctx.Err() != nil {
+			if truncated {
+				err = io.ErrUnexpectedEOF
+			}
+			return n, err
+		}
+
+		// Resume with a Range request on the next read
+		r.retries--
+		r.body.Close()
+		r.body = nil
+		if n > 0 {
+			return n, nil
+		}
+	}
+}
+
+func (r *downloadReader) Close() error {
+	r.closed = true
+	if r.body == nil {
+		return nil
+	}
+	err := r.body.Close()
+	r.body = nil
+	return err
+}
+
+// DownloadRecordingsOptions controls how DownloadRecordings fans out over
+// recordings
+type DownloadRecordingsOptions struct {
+	// Concurrency is the maximum number of downloads in flight, 4 by default
+	Concurrency int
+	// Progress is called with the progress of every single download
+	Progress func(recording Recording, received, total int64)
+	// MaxRetries is passed on to every download, see DownloadOptions
+	MaxRetries int
+}
+
+// DownloadResult is the outcome of downloading a single recording
+type DownloadResult struct {
+	Recording Recording
+	Path      string
+	// SHA256 is the hex encoded checksum of the written file
+	SHA256 string
+	Err    error
+}
+
+// DownloadRecordings downloads every recording matching filter into dir,
+// named after camera, start time and recording ID. Files are written
+// atomically, and recordings whose file already exists are skipped, so an
+// interrupted run can simply be repeated. Results come in listing order.
+func (c *SurveillanceStationClient) DownloadRecordings(ctx context.Context, filter *ListRecordingsOptions, dir string, opts *DownloadRecordingsOptions) ([]DownloadResult, error) {
+	if opts == nil {
+		opts = &DownloadRecordingsOptions{}
+	}
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = 4
+	}
+
+	var recordings []Recording
+	it := c.IterateRecordings(filter)
+	for it.Next(ctx) {
+		recordings = append(recordings, it.Recording())
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	results := make([]DownloadResult, len(recordings))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, recording := range recordings {
+		path := filepath.Join(dir, fmt.Sprintf("%d-%s-%d.mp4",
+			recording.CameraID, recording.Start().UTC().Format("20060102-150405"), recording.ID))
+		results[i] = DownloadResult{Recording: recording, Path: path}
+		if _, err := os.Stat(path); err == nil {
+			continue
+		}
+
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			results[i].Err = ctx.Err()
+			continue
+		}
+
+		wg.Add(1)
+		go func(result *DownloadResult) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			result.SHA256, result.Err = c.downloadToFile(ctx, result.Recording, result.Path, opts)
+		}(&results[i])
+	}
+	wg.Wait()
+
+	return results, nil
+}
+
+// downloadToFile downloads a recording to path through a temporary file
+// and returns the checksum of what was written
+func (c *SurveillanceStationClient) downloadToFile(ctx context.Context, recording Recording, path string, opts *DownloadRecordingsOptions) (string, error) {
+	sum := sha256.New()
+	downloadOpts := &DownloadOptions{Hash: sum, MaxRetries: opts.MaxRetries}
+	if opts.Progress != nil {
+		downloadOpts.Progress = func(received, total int64) {
+			opts.Progress(recording, received, total)
+		}
+	}
+
+	r, err := c.DownloadRecordingContext(ctx, recording, downloadOpts)
+	if err != nil {
+		return "", err
+	}
+	defer r.Close()
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".download-*")
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return "", fmt.Errorf("failed to download recording ID %d: %w", recording.ID, err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return "", err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return "", err
+	}
+	return hex.EncodeToString(sum.Sum(nil)), nil
+}
@@ -0,0 +1,175 @@
+package sssg
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDownloadRecordingResumes(t *testing.T) {
+	const content = "0123456789"
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("id"); got != "42" {
+			t.Errorf("Unexpected recording ID: %s", got)
+		}
+		w.Header().Set("Content-Type", "video/mp4")
+		switch r.Header.Get("Range") {
+		case "":
+			// Announce the full size but drop the connection half way
+			w.Header().Set("Content-Length", "10")
+			w.Write([]byte(content[:4]))
+		case "bytes=4-":
+			w.Header().Set("Content-Range", "bytes 4-9/10")
+			w.WriteHeader(http.StatusPartialContent)
+			w.Write([]byte(content[4:]))
+		default:
+			t.Errorf("Unexpected range: %s", r.Header.Get("Range"))
+		}
+	})
+
+	sum := sha256.New()
+	var received, total int64
+	r, err := client.DownloadRecording(Recording{ID: 42}, &DownloadOptions{
+		Hash:     sum,
+		Progress: func(n, size int64) { received, total = n, size },
+	})
+	if err != nil {
+		t.Fatalf("Failed to download recording: %v", err)
+	}
+	defer r.Close()
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("Failed to read recording: %v", err)
+	}
+	if string(data) != content {
+		t.Errorf("Unexpected content: %q", data)
+	}
+	if received != 10 || total != 10 {
+		t.Errorf("Unexpected progress: %d/%d", received, total)
+	}
+	want := sha256.Sum256([]byte(content))
+	if got := sum.Sum(nil); string(got) != string(want[:]) {
+		t.Errorf("Unexpected checksum: %x", got)
+	}
+}
+
+func TestDownloadRange(t *testing.T) {
+	var polls int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		switch query.Get("method") {
+		case "RangeExport":
+			if query.Get("camId") != "3" || query.Get("fromTime") != "1700000000" || query.Get("toTime") != "1700000600" {
+				t.Errorf("Unexpected export parameters: %v", query)
+			}
+			w.Write([]byte(`{"success":true,"data":{"dlid":7}}`))
+		case "GetRangeExportProgress":
+			if atomic.AddInt32(&polls, 1) == 1 {
+				w.Write([]byte(`{"success":true,"data":{"progress":50}}`))
+				return
+			}
+			w.Write([]byte(`{"success":true,"data":{"progress":100,"fileExt":"mp4"}}`))
+		case "OnRangeExportDone":
+			if query.Get("dlid") != "7" || filepath.Ext(query.Get("filename")) != ".mp4" {
+				t.Errorf("Unexpected download parameters: %v", query)
+			}
+			w.Header().Set("Content-Type", "video/mp4")
+			w.Write([]byte("clip"))
+		}
+	})
+
+	from := time.Unix(1700000000, 0)
+	r, err := client.DownloadRange(3, from, from.Add(10*time.Minute), &DownloadOptions{PollInterval: time.Millisecond})
+	if err != nil {
+		t.Fatalf("Failed to download range: %v", err)
+	}
+	defer r.Close()
+
+	data, _ := io.ReadAll(r)
+	if string(data) != "clip" || polls != 2 {
+		t.Errorf("Unexpected content %q after %d polls", data, polls)
+	}
+}
+
+func TestDownloadRecordings(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("method") {
+		case "List":
+			w.Write([]byte(`{"success":true,"data":{"total":2,"recordings":[
+				{"id":1,"cameraId":5,"startTime":1700000000},
+				{"id":2,"cameraId":5,"startTime":1700000100}
+			]}}`))
+		case "Download":
+			if r.URL.Query().Get("id") == "1" {
+				t.Errorf("Expected the existing recording to be skipped")
+			}
+			w.Header().Set("Content-Type", "video/mp4")
+			w.Write([]byte("recording " + r.URL.Query().Get("id")))
+		}
+	})
+
+	dir := t.TempDir()
+	existing := filepath.Join(dir, "5-20231114-221320-1.mp4")
+	if err := os.WriteFile(existing, []byte("done"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := client.DownloadRecordings(context.Background(), nil, dir, &DownloadRecordingsOptions{Concurrency: 2})
+	if err != nil {
+		t.Fatalf("Failed to download recordings: %v", err)
+	}
+	if len(results) != 2 || results[0].Path != existing {
+		t.Fatalf("Unexpected results: %+v", results)
+	}
+
+	data, err := os.ReadFile(results[1].Path)
+	if err != nil || string(data) != "recording 2" {
+		t.Fatalf("Unexpected download %q: %v", data, err)
+	}
+	sum := sha256.Sum256(data)
+	if results[1].Err != nil || results[1].SHA256 != hex.EncodeToString(sum[:]) {
+		t.Errorf("Unexpected result: %+v", results[1])
+	}
+}
+
+func TestDownloadRangeFailedExport(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		progress string
+	}{
+		{"negative progress", `{"progress":-1}`},
+		{"error field", `{"progress":10,"error":1}`},
+		{"stalled", `{"progress":10}`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Query().Get("method") {
+				case "RangeExport":
+					w.Write([]byte(`{"success":true,"data":{"dlid":7}}`))
+				case "GetRangeExportProgress":
+					w.Write([]byte(`{"success":true,"data":` + tc.progress + `}`))
+				default:
+					t.Errorf("Unexpected download of a failed export")
+				}
+			})
+
+			from := time.Unix(1700000000, 0)
+			_, err := client.DownloadRange(3, from, from.Add(time.Minute), &DownloadOptions{
+				PollInterval:  time.Millisecond,
+				MaxExportWait: 20 * time.Millisecond,
+			})
+			if !errors.Is(err, ErrExportFailed) {
+				t.Errorf("Expected ErrExportFailed, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,257 @@
+package sssg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrUnfilteredDelete is returned when DeleteRecordingsMatching is asked to
+// delete recordings without narrowing them down by camera or time
+var ErrUnfilteredDelete = errors.New("refusing to delete recordings without a camera or time filter")
+
+// LockRecordings protects recordings from rotation
+func (c *SurveillanceStationClient) LockRecordings(ids ...int) error {
+	return c.LockRecordingsContext(context.Background(), ids...)
+}
+
+// LockRecordingsContext protects recordings from rotation, bound to ctx
+func (c *SurveillanceStationClient) LockRecordingsContext(ctx context.Context, ids ...int) error {
+	return c.recordingAction(ctx, "Lock", "lock", ids)
+}
+
+// UnlockRecordings lets recordings be rotated again
+func (c *SurveillanceStationClient) UnlockRecordings(ids ...int) error {
+	return c.UnlockRecordingsContext(context.Background(), ids...)
+}
+
+// UnlockRecordingsContext lets recordings be rotated again, bound to ctx
+func (c *SurveillanceStationClient) UnlockRecordingsContext(ctx context.Context, ids ...int) error {
+	return c.recordingAction(ctx, "UnLock", "unlock", ids)
+}
+
+// DeleteRecordings deletes recordings
+func (c *SurveillanceStationClient) DeleteRecordings(ids ...int) error {
+	return c.DeleteRecordingsContext(context.Background(), ids...)
+}
+
+// DeleteRecordingsContext deletes recordings, bound to ctx
+func (c *SurveillanceStationClient) DeleteRecordingsContext(ctx context.Context, ids ...int) error {
+	return c.recordingAction(ctx, "Delete", "delete", ids)
+}
+
+// recordingAction calls method of the Recording API for all ids at once
+func (c *SurveillanceStationClient) recordingAction(ctx context.Context, method, verb string, ids []int) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	params := url.Values{}
+	params.Set("idList", joinIDs(ids))
+	if err := c.callAPI(ctx, "SYNO.SurveillanceStation.Recording", method, 1, 6, params, nil); err != nil {
+		return fmt.Errorf("failed to %s recordings %s: %w", verb, joinIDs(ids), err)
+	}
+	return nil
+}
+
+// RecordingChangeOptions controls how recordings selected by a filter are
+// changed
+type RecordingChangeOptions struct {
+	// DryRun only lists the recordings that would be affected
+	DryRun bool
+	// All lets DeleteRecordingsMatching run without a camera or time filter,
+	// deleting every unlocked recording on the NAS
+	All bool
+}
+
+// RecordingPlan lists the recordings a filtered change affects
+type RecordingPlan struct {
+	Recordings []Recording
+	// Warnings flags cameras whose locked recordings would outgrow their
+	// retention settings, only set when locking
+	Warnings []QuotaWarning
+}
+
+// IDs returns the IDs of the affected recordings
+func (p *RecordingPlan) IDs() []int {
+	ids := make([]int, len(p.Recordings))
+	for i, recording := range p.Recordings {
+		ids[i] = recording.ID
+	}
+	return ids
+}
+
+// QuotaWarning reports a camera whose locked recordings, which are never
+// rotated, would take up more than its retention settings allow
+type QuotaWarning struct {
+	CameraID int
+	// LockedSize is the size in MB of all locked recordings of the camera
+	// once the lock is applied, and QuotaSize its RecordingKeepSize in MB
+	LockedSize float64
+	QuotaSize  float64
+	// BeyondKeepDays counts the newly locked recordings older than the
+	// RecordingKeepDays of the camera
+	BeyondKeepDays int
+}
+
+func (w QuotaWarning) String() string {
+	var problems []string
+	if w.QuotaSize > 0 && w.LockedSize > w.QuotaSize {
+		problems = append(problems, fmt.Sprintf("%.0f MB locked exceeds the %.0f MB quota", w.LockedSize, w.QuotaSize))
+	}
+	if w.BeyondKeepDays > 0 {
+		problems = append(problems, fmt.Sprintf("%d recordings kept beyond the retention days", w.BeyondKeepDays))
+	}
+	return fmt.Sprintf("camera ID %d: %s", w.CameraID, strings.Join(problems, ", "))
+}
+
+// LockRecordingsMatching locks every unlocked recording matching filter
+// and warns about cameras whose retention settings the lock would exceed
+func (c *SurveillanceStationClient) LockRecordingsMatching(filter *ListRecordingsOptions, opts *RecordingChangeOptions) (*RecordingPlan, error) {
+	return c.LockRecordingsMatchingContext(context.Background(), filter, opts)
+}
+
+// LockRecordingsMatchingContext locks every unlocked recording matching
+// filter, bound to ctx
+func (c *SurveillanceStationClient) LockRecordingsMatchingContext(ctx context.Context, filter *ListRecordingsOptions, opts *RecordingChangeOptions) (*RecordingPlan, error) {
+	plan, err := c.planRecordings(ctx, filter, false)
+	if err != nil {
+		return nil, err
+	}
+	if plan.Warnings, err = c.quotaWarnings(ctx, plan.Recordings, time.Now()); err != nil {
+		return nil, err
+	}
+	return plan, c.applyPlan(ctx, plan, opts, c.LockRecordingsContext)
+}
+
+// UnlockRecordingsMatching unlocks every locked recording matching filter
+func (c *SurveillanceStationClient) UnlockRecordingsMatching(filter *ListRecordingsOptions, opts *RecordingChangeOptions) (*RecordingPlan, error) {
+	return c.UnlockRecordingsMatchingContext(context.Background(), filter, opts)
+}
+
+// UnlockRecordingsMatchingContext unlocks every locked recording matching
+// filter, bound to ctx
+func (c *SurveillanceStationClient) UnlockRecordingsMatchingContext(ctx context.Context, filter *ListRecordingsOptions, opts *RecordingChangeOptions) (*RecordingPlan, error) {
+	plan, err := c.planRecordings(ctx, filter, true)
+	if err != nil {
+		return nil, err
+	}
+	return plan, c.applyPlan(ctx, plan, opts, c.UnlockRecordingsContext)
+}
+
+// DeleteRecordingsMatching deletes every recording matching filter. Locked
+// recordings are never deleted, unlock them first. A filter without
+// CameraIDs, From or To fails with ErrUnfilteredDelete unless opts.All is set.
+func (c *SurveillanceStationClient) DeleteRecordingsMatching(filter *ListRecordingsOptions, opts *RecordingChangeOptions) (*RecordingPlan, error) {
+	return c.DeleteRecordingsMatchingContext(context.Background(), filter, opts)
+}
+
+// DeleteRecordingsMatchingContext deletes every unlocked recording matching
+// filter, bound to ctx
+func (c *SurveillanceStationClient) DeleteRecordingsMatchingContext(ctx context.Context, filter *ListRecordingsOptions, opts *RecordingChangeOptions) (*RecordingPlan, error) {
+	unfiltered := filter == nil || (len(filter.CameraIDs) == 0 && filter.From.IsZero() && filter.To.IsZero())
+	if unfiltered && (opts == nil || !opts.All) {
+		return nil, ErrUnfilteredDelete
+	}
+
+	plan, err := c.planRecordings(ctx, filter, false)
+	if err != nil {
+		return nil, err
+	}
+	return plan, c.applyPlan(ctx, plan, opts, c.DeleteRecordingsContext)
+}
+
+// planRecordings collects the recordings matching filter whose locked state
+// equals locked
+func (c *SurveillanceStationClient) planRecordings(ctx context.Context, filter *ListRecordingsOptions, locked bool) (*RecordingPlan, error) {
+	var opts ListRecordingsOptions
+	if filter != nil {
+		opts = *filter
+	}
+	opts.Locked = &locked
+
+	plan := &RecordingPlan{}
+	it := c.IterateRecordings(&opts)
+	for it.Next(ctx) {
+		plan.Recordings = append(plan.Recordings, it.Recording())
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// applyPlan runs action on the recordings of plan unless it is a dry run
+func (c *SurveillanceStationClient) applyPlan(ctx context.Context, plan *RecordingPlan, opts *RecordingChangeOptions, action func(context.Context, ...int) error) error {
+	if len(plan.Recordings) == 0 || (opts != nil && opts.DryRun) {
+		return nil
+	}
+	return action(ctx, plan.IDs()...)
+}
+
+// quotaWarnings checks the recordings about to be locked against the
+// retention settings of their cameras
+func (c *SurveillanceStationClient) quotaWarnings(ctx context.Context, recordings []Recording, now time.Time) ([]QuotaWarning, error) {
+	if len(recordings) == 0 {
+		return nil, nil
+	}
+	cameras, err := c.ListCamerasContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[int]Camera, len(cameras))
+	for _, camera := range cameras {
+		byID[camera.ID] = camera
+	}
+
+	byCamera := make(map[int][]Recording)
+	var order []int
+	for _, recording := range recordings {
+		if _, ok := byCamera[recording.CameraID]; !ok {
+			order = append(order, recording.CameraID)
+		}
+		byCamera[recording.CameraID] = append(byCamera[recording.CameraID], recording)
+	}
+
+	var warnings []QuotaWarning
+	for _, cameraID := range order {
+		camera, ok := byID[cameraID]
+		if !ok {
+			continue
+		}
+		warning := QuotaWarning{CameraID: cameraID}
+
+		if camera.EnableRecordingKeepDays && camera.RecordingKeepDays > 0 {
+			cutoff := now.AddDate(0, 0, -camera.RecordingKeepDays)
+			for _, recording := range byCamera[cameraID] {
+				if recording.Start().Before(cutoff) {
+					warning.BeyondKeepDays++
+				}
+			}
+		}
+
+		if gb, err := strconv.ParseFloat(camera.RecordingKeepSize, 64); err == nil && camera.EnableRecordingKeepSize && gb > 0 {
+			warning.QuotaSize = gb * 1024
+			for _, recording := range byCamera[cameraID] {
+				warning.LockedSize += recording.Size
+			}
+
+			locked := true
+			it := c.IterateRecordings(&ListRecordingsOptions{CameraIDs: []int{cameraID}, Locked: &locked})
+			for it.Next(ctx) {
+				warning.LockedSize += it.Recording().Size
+			}
+			if err := it.Err(); err != nil {
+				return nil, err
+			}
+		}
+
+		if warning.BeyondKeepDays > 0 || warning.LockedSize > warning.QuotaSize {
+			warnings = append(warnings, warning)
+		}
+	}
+	return warnings, nil
+}
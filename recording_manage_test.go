@@ -0,0 +1,85 @@
+package sssg
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestLockRecordingsMatchingDryRun(t *testing.T) {
+	old := time.Now().AddDate(0, 0, -10).Unix()
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		switch query.Get("api") + "." + query.Get("method") {
+		case "SYNO.SurveillanceStation.Camera.List":
+			w.Write([]byte(`{"success":true,"data":{"cameras":[{"id":1,
+				"enableRecordingKeepDays":true,"recordingKeepDays":7,
+				"enableRecordingKeepSize":true,"recordingKeepSize":"1"}]}}`))
+		case "SYNO.SurveillanceStation.Recording.List":
+			if query.Get("locked") == "1" {
+				w.Write([]byte(`{"success":true,"data":{"total":1,"recordings":[
+					{"id":9,"cameraId":1,"locked":true,"eventSize":800}]}}`))
+				return
+			}
+			w.Write([]byte(`{"success":true,"data":{"total":2,"recordings":[
+				{"id":10,"cameraId":1,"startTime":` + strconv.FormatInt(old, 10) + `,"eventSize":300},
+				{"id":11,"cameraId":1,"locked":true,"eventSize":100}]}}`))
+		default:
+			t.Errorf("Unexpected call during dry run: %v", query)
+		}
+	})
+
+	plan, err := client.LockRecordingsMatching(nil, &RecordingChangeOptions{DryRun: true})
+	if err != nil {
+		t.Fatalf("Failed to plan lock: %v", err)
+	}
+	if ids := plan.IDs(); len(ids) != 1 || ids[0] != 10 {
+		t.Errorf("Expected only the unlocked recording, got %v", ids)
+	}
+	if len(plan.Warnings) != 1 {
+		t.Fatalf("Expected a quota warning, got %+v", plan.Warnings)
+	}
+	warning := plan.Warnings[0]
+	if warning.LockedSize != 1100 || warning.QuotaSize != 1024 || warning.BeyondKeepDays != 1 {
+		t.Errorf("Unexpected warning: %+v", warning)
+	}
+}
+
+func TestDeleteRecordingsMatching(t *testing.T) {
+	var deleted string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("method") {
+		case "List":
+			if got := r.URL.Query().Get("cameraIds"); got != "2" {
+				t.Errorf("Unexpected cameraIds: %s", got)
+			}
+			w.Write([]byte(`{"success":true,"data":{"total":3,"recordings":[
+				{"id":1,"cameraId":2},{"id":2,"cameraId":2,"locked":true},{"id":3,"cameraId":2}]}}`))
+		case "Delete":
+			deleted = r.URL.Query().Get("idList")
+			w.Write([]byte(`{"success":true}`))
+		}
+	})
+
+	plan, err := client.DeleteRecordingsMatching(&ListRecordingsOptions{CameraIDs: []int{2}}, nil)
+	if err != nil {
+		t.Fatalf("Failed to delete recordings: %v", err)
+	}
+	if deleted != "1,3" || len(plan.Recordings) != 2 {
+		t.Errorf("Expected locked recordings to be spared, deleted %q", deleted)
+	}
+}
+
+func TestDeleteRecordingsMatchingRefusesUnfiltered(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Unexpected request: %v", r.URL.Query())
+	})
+
+	for _, filter := range []*ListRecordingsOptions{nil, {Reasons: []RecordingReason{RecordingMotion}}} {
+		if _, err := client.DeleteRecordingsMatching(filter, &RecordingChangeOptions{DryRun: true}); !errors.Is(err, ErrUnfilteredDelete) {
+			t.Errorf("Expected ErrUnfilteredDelete for %+v, got %v", filter, err)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package sssg
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestListRecordingsFilters(t *testing.T) {
+	from := time.Unix(1700000000, 0)
+	to := from.Add(time.Hour)
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		for param, want := range map[string]string{
+			"cameraIds": "1,2",
+			"fromTime":  "1700000000",
+			"toTime":    "1700003600",
+			"locked":    "1",
+			"limit":     "10",
+		} {
+			if got := query.Get(param); got != want {
+				t.Errorf("Expected %s=%s, got %q", param, want, got)
+			}
+		}
+		w.Write([]byte(`{"success":true,"data":{"total":3,"recordings":[
+			{"id":10,"cameraId":1,"startTime":1700000000,"stopTime":1700000060,"reason":2,"locked":true},
+			{"id":11,"cameraId":2,"startTime":1700000100,"stopTime":1700000200,"reason":1,"locked":true},
+			{"id":12,"cameraId":2,"startTime":1700000300,"stopTime":1700000400,"reason":2,"locked":false}
+		]}}`))
+	})
+
+	locked := true
+	list, err := client.ListRecordings(&ListRecordingsOptions{
+		Limit:     10,
+		CameraIDs: []int{1, 2},
+		From:      from,
+		To:        to,
+		Reasons:   []RecordingReason{RecordingMotion},
+		Locked:    &locked,
+	})
+	if err != nil {
+		t.Fatalf("Failed to list recordings: %v", err)
+	}
+	if len(list.Recordings) != 1 || list.Recordings[0].ID != 10 || list.Total != 3 {
+		t.Fatalf("Unexpected recordings: %+v", list)
+	}
+	if got := list.Recordings[0].Duration(); got != time.Minute {
+		t.Errorf("Expected a one minute recording, got %s", got)
+	}
+}
+
+func TestIterateRecordings(t *testing.T) {
+	tests := []struct {
+		name    string
+		limit   int
+		maxPage int
+	}{
+		{name: "Full pages", limit: 2, maxPage: 100},
+		{name: "Server caps page size", limit: 10, maxPage: 2},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			testIterateRecordings(t, testCase.limit, testCase.maxPage)
+		})
+	}
+}
+
+func testIterateRecordings(t *testing.T, limit, maxPage int) {
+	const total = 5
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		if limit > maxPage {
+			limit = maxPage
+		}
+
+		var recordings string
+		for id := offset; id < offset+limit && id < total; id++ {
+			if recordings != "" {
+				recordings += ","
+			}
+			recordings += fmt.Sprintf(`{"id":%d,"cameraId":1}`, id)
+		}
+		fmt.Fprintf(w, `{"success":true,"data":{"total":%d,"recordings":[%s]}}`, total, recordings)
+	})
+
+	it := client.IterateRecordings(&ListRecordingsOptions{Limit: limit})
+	var ids []int
+	for it.Next(context.Background()) {
+		ids = append(ids, it.Recording().ID)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("Failed to iterate recordings: %v", err)
+	}
+	if fmt.Sprint(ids) != "[0 1 2 3 4]" {
+		t.Errorf("Unexpected recording IDs: %v", ids)
+	}
+}
@@ -0,0 +1,156 @@
+package sssg
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// apiResponse is the envelope every Synology WebAPI call responds with
+type apiResponse struct {
+	Success bool            `json:"success"`
+	Data    json.RawMessage `json:"data"`
+	Error   *struct {
+		Code int `json:"code"`
+	} `json:"error"`
+}
+
+// get performs a GET request against endpoint, bound to ctx
+func (c *SurveillanceStationClient) get(ctx context.Context, endpoint string, params url.Values) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+// do performs a GET request against endpoint and decodes the response with
+// decodeResponse
+func (c *SurveillanceStationClient) do(ctx context.Context, endpoint, api, method string, params url.Values, data interface{}) error {
+	resp, err := c.get(ctx, endpoint, params)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return decodeResponse(resp.Body, api, method, data)
+}
+
+// decodeResponse decodes the "data" field of the response envelope into
+// data, which may be nil if the caller does not care about the payload. An
+// unsuccessful response is returned as *APIError labelled with api and method.
+func decodeResponse(r io.Reader, api, method string, data interface{}) error {
+	var result apiResponse
+	if err := json.NewDecoder(r).Decode(&result); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	if !result.Success {
+		apiErr := &APIError{API: api, Method: method}
+		if result.Error != nil {
+			apiErr.Code = result.Error.Code
+		}
+		return apiErr
+	}
+
+	if data == nil || len(result.Data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(result.Data, data)
+}
+
+// prepareAPI resolves the endpoint of api, at the highest version between
+// minVersion and maxVersion that the NAS supports, and sets the api, method
+// and version parameters accordingly
+func (c *SurveillanceStationClient) prepareAPI(ctx context.Context, api, method string, minVersion, maxVersion int, params url.Values) (string, url.Values, error) {
+	endpoint, version, err := c.resolveAPI(ctx, api, minVersion, maxVersion)
+	if err != nil {
+		return "", nil, err
+	}
+	if params == nil {
+		params = url.Values{}
+	}
+	params.Set("api", api)
+	params.Set("method", method)
+	params.Set("version", strconv.Itoa(version))
+	return endpoint, params, nil
+}
+
+// callAPI invokes method of api using the current session, see prepareAPI
+// and withSession
+func (c *SurveillanceStationClient) callAPI(ctx context.Context, api, method string, minVersion, maxVersion int, params url.Values, data interface{}) error {
+	endpoint, params, err := c.prepareAPI(ctx, api, method, minVersion, maxVersion, params)
+	if err != nil {
+		return err
+	}
+
+	return c.withSession(ctx, func(sid string) error {
+		params.Set("_sid", sid)
+		return c.do(ctx, endpoint, api, method, params, data)
+	})
+}
+
+// withSession calls fn with the current session id. If the session turns
+// out to be expired and credentials are available, it logs in again and
+// calls fn once more with the new session id.
+func (c *SurveillanceStationClient) withSession(ctx context.Context, fn func(sid string) error) error {
+	sid := c.sessionID()
+	err := fn(sid)
+	if !errors.Is(err, ErrSessionExpired) || c.Username == "" {
+		return err
+	}
+
+	if loginErr := c.relogin(ctx, sid); loginErr != nil {
+		return fmt.Errorf("%w (re-login failed: %v)", err, loginErr)
+	}
+	return fn(c.sessionID())
+}
+
+// relogin logs in again, unless another goroutine already replaced staleSid
+// with a fresh session while we were waiting for the lock
+func (c *SurveillanceStationClient) relogin(ctx context.Context, staleSid string) error {
+	c.loginMu.Lock()
+	defer c.loginMu.Unlock()
+
+	if c.sessionID() != staleSid {
+		return nil
+	}
+	return c.LoginContext(ctx)
+}
+
+// sessionID returns the current session id
+func (c *SurveillanceStationClient) sessionID() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.Session
+}
+
+// setSession replaces the current session id and restarts its age
+func (c *SurveillanceStationClient) setSession(sid string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.Session = sid
+	c.loginTime = time.Now()
+}
+
+// joinIDs formats ids as the comma separated list the WebAPI expects
+func joinIDs(ids []int) string {
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = strconv.Itoa(id)
+	}
+	return strings.Join(parts, ",")
+}
+
+// cameraParams returns the parameters addressing a single camera
+func cameraParams(cameraID int) url.Values {
+	params := url.Values{}
+	params.Set("cameraId", strconv.Itoa(cameraID))
+	return params
+}
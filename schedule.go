@@ -0,0 +1,217 @@
+package sssg
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	scheduleSlotsPerDay = 48
+	scheduleSlotMinutes = 30
+	scheduleLength      = 7 * scheduleSlotsPerDay
+)
+
+// ErrInvalidSchedule is returned when a schedule string or range cannot be parsed
+var ErrInvalidSchedule = errors.New("invalid schedule")
+
+// WeeklySchedule is the decoded form of the 336 character schedule strings
+// used by Camera.RecordSchedule, HomeModeInfo.ModeSchedule,
+// HomeModeInfo.RecSchedule and ActionRule.ActSchedule. Each slot covers half
+// an hour, the first one starting on Sunday at 00:00.
+type WeeklySchedule [scheduleLength]bool
+
+// ParseWeeklySchedule decodes a schedule string of 336 "0" and "1" digits
+func ParseWeeklySchedule(s string) (WeeklySchedule, error) {
+	var w WeeklySchedule
+	if len(s) != scheduleLength {
+		return w, fmt.Errorf("%w: expected %d slots, got %d", ErrInvalidSchedule, scheduleLength, len(s))
+	}
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '0':
+		case '1':
+			w[i] = true
+		default:
+			return w, fmt.Errorf("%w: unexpected %q at slot %d", ErrInvalidSchedule, s[i], i)
+		}
+	}
+	return w, nil
+}
+
+// String encodes the schedule back into the Surveillance Station format
+func (w WeeklySchedule) String() string {
+	var b strings.Builder
+	b.Grow(scheduleLength)
+	for _, active := range w {
+		if active {
+			b.WriteByte('1')
+		} else {
+			b.WriteByte('0')
+		}
+	}
+	return b.String()
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (w WeeklySchedule) MarshalText() ([]byte, error) {
+	return []byte(w.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (w *WeeklySchedule) UnmarshalText(text []byte) error {
+	parsed, err := ParseWeeklySchedule(string(text))
+	if err != nil {
+		return err
+	}
+	*w = parsed
+	return nil
+}
+
+// slotIndex returns the slot covering t, in t's location
+func slotIndex(t time.Time) int {
+	return int(t.Weekday())*scheduleSlotsPerDay + (t.Hour()*60+t.Minute())/scheduleSlotMinutes
+}
+
+// Active reports whether the schedule is active at t, in t's location
+func (w WeeklySchedule) Active(t time.Time) bool {
+	return w[slotIndex(t)]
+}
+
+// NextTransition returns the first slot boundary after t at which the
+// schedule switches between active and inactive. It returns false when the
+// schedule is constant over the whole week.
+func (w WeeklySchedule) NextTransition(t time.Time) (time.Time, bool) {
+	current := w.Active(t)
+	year, month, day := t.Date()
+	slotOfDay := (t.Hour()*60 + t.Minute()) / scheduleSlotMinutes
+
+	for k := 1; k <= scheduleLength; k++ {
+		boundary := time.Date(year, month, day, 0, (slotOfDay+k)*scheduleSlotMinutes, 0, 0, t.Location())
+		if w.Active(boundary) != current {
+			return boundary, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// SetRange marks the slots of day from start to end (offsets since
+// midnight, on half-hour boundaries) as active or inactive. An end before
+// start wraps past midnight into the following day, and an empty range
+// where both are equal is rejected; use 00:00-24:00 for a whole day.
+func (w *WeeklySchedule) SetRange(day time.Weekday, start, end time.Duration, active bool) error {
+	slot := time.Duration(scheduleSlotMinutes) * time.Minute
+	if start%slot != 0 || end%slot != 0 {
+		return fmt.Errorf("%w: %v-%v is not aligned to half hours", ErrInvalidSchedule, start, end)
+	}
+	if start < 0 || start >= 24*time.Hour || end < 0 || end > 24*time.Hour {
+		return fmt.Errorf("%w: %v-%v is outside of a day", ErrInvalidSchedule, start, end)
+	}
+	if start == end {
+		return fmt.Errorf("%w: %v-%v is empty", ErrInvalidSchedule, start, end)
+	}
+
+	from := int(day)*scheduleSlotsPerDay + int(start/slot)
+	to := int(day)*scheduleSlotsPerDay + int(end/slot)
+	if to <= from {
+		to += scheduleSlotsPerDay
+	}
+	for i := from; i < to; i++ {
+		w[i%scheduleLength] = active
+	}
+	return nil
+}
+
+var weekdayNames = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// ParseScheduleRanges builds a schedule from human readable ranges such as
+// "Mon-Fri 08:00-18:00; Sat,Sun 10:00-12:00,14:00-16:00". Entries are
+// separated by semicolons, "Daily" selects every day and a range ending
+// before it starts, like "22:00-06:00", continues into the next day.
+func ParseScheduleRanges(spec string) (WeeklySchedule, error) {
+	var w WeeklySchedule
+	for _, entry := range strings.Split(spec, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		fields := strings.Fields(entry)
+		if len(fields) != 2 {
+			return w, fmt.Errorf("%w: expected \"<days> <ranges>\", got %q", ErrInvalidSchedule, entry)
+		}
+		days, err := parseDays(fields[0])
+		if err != nil {
+			return w, err
+		}
+
+		for _, r := range strings.Split(fields[1], ",") {
+			bounds := strings.Split(r, "-")
+			if len(bounds) != 2 {
+				return w, fmt.Errorf("%w: invalid time range %q", ErrInvalidSchedule, r)
+			}
+			start, err := parseClock(bounds[0])
+			if err != nil {
+				return w, err
+			}
+			end, err := parseClock(bounds[1])
+			if err != nil {
+				return w, err
+			}
+			for _, day := range days {
+				if err := w.SetRange(day, start, end, true); err != nil {
+					return w, err
+				}
+			}
+		}
+	}
+	return w, nil
+}
+
+// parseDays parses "Mon", "Mon-Fri", "Sat,Sun" or "Daily" into weekdays
+func parseDays(s string) ([]time.Weekday, error) {
+	if strings.EqualFold(s, "daily") {
+		return []time.Weekday{time.Sunday, time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday}, nil
+	}
+
+	var days []time.Weekday
+	for _, part := range strings.Split(s, ",") {
+		bounds := strings.Split(part, "-")
+		if len(bounds) > 2 {
+			return nil, fmt.Errorf("%w: invalid day range %q", ErrInvalidSchedule, part)
+		}
+		first, ok := weekdayNames[strings.ToLower(bounds[0])]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown day %q", ErrInvalidSchedule, bounds[0])
+		}
+		last := first
+		if len(bounds) == 2 {
+			if last, ok = weekdayNames[strings.ToLower(bounds[1])]; !ok {
+				return nil, fmt.Errorf("%w: unknown day %q", ErrInvalidSchedule, bounds[1])
+			}
+		}
+		for d := first; ; d = (d + 1) % 7 {
+			days = append(days, d)
+			if d == last {
+				break
+			}
+		}
+	}
+	return days, nil
+}
+
+// parseClock parses "HH:MM" into an offset since midnight, allowing "24:00"
+func parseClock(s string) (time.Duration, error) {
+	var hours, minutes int
+	if _, err := fmt.Sscanf(s, "%d:%d", &hours, &minutes); err != nil || len(s) != 5 {
+		return 0, fmt.Errorf("%w: invalid time %q", ErrInvalidSchedule, s)
+	}
+	if hours < 0 || hours > 24 || minutes < 0 || minutes > 59 || (hours == 24 && minutes != 0) {
+		return 0, fmt.Errorf("%w: invalid time %q", ErrInvalidSchedule, s)
+	}
+	return time.Duration(hours)*time.Hour + time.Duration(minutes)*time.Minute, nil
+}
@@ -0,0 +1,95 @@
+package sssg
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseWeeklyScheduleRoundTrip(t *testing.T) {
+	original := strings.Repeat("0", 16) + strings.Repeat("1", 20) + strings.Repeat("0", scheduleLength-36)
+
+	schedule, err := ParseWeeklySchedule(original)
+	if err != nil {
+		t.Fatalf("Failed to parse schedule: %v", err)
+	}
+	if got := schedule.String(); got != original {
+		t.Errorf("Mismatch between original and re-encoded schedule:\nOriginal: %s\nRe-encoded: %s", original, got)
+	}
+}
+
+func TestParseWeeklyScheduleValidation(t *testing.T) {
+	testCases := []struct {
+		name     string
+		schedule string
+		parse    func(string) (WeeklySchedule, error)
+	}{
+		{"Too short", strings.Repeat("1", scheduleLength-1), ParseWeeklySchedule},
+		{"Too long", strings.Repeat("1", scheduleLength+1), ParseWeeklySchedule},
+		{"Invalid digit", strings.Repeat("1", scheduleLength-1) + "2", ParseWeeklySchedule},
+		{"Empty range", "Mon 08:00-08:00", ParseScheduleRanges},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			if _, err := testCase.parse(testCase.schedule); !errors.Is(err, ErrInvalidSchedule) {
+				t.Errorf("Expected ErrInvalidSchedule, got: %v", err)
+			}
+		})
+	}
+}
+
+func TestParseScheduleRanges(t *testing.T) {
+	schedule, err := ParseScheduleRanges("Mon-Fri 08:00-18:00; Sat 22:00-02:00")
+	if err != nil {
+		t.Fatalf("Failed to parse ranges: %v", err)
+	}
+
+	// 2025-03-10 is a Monday
+	testCases := []struct {
+		time   time.Time
+		active bool
+	}{
+		{time.Date(2025, 3, 10, 7, 59, 0, 0, time.UTC), false},
+		{time.Date(2025, 3, 10, 8, 0, 0, 0, time.UTC), true},
+		{time.Date(2025, 3, 14, 17, 30, 0, 0, time.UTC), true},
+		{time.Date(2025, 3, 14, 18, 0, 0, 0, time.UTC), false},
+		{time.Date(2025, 3, 15, 23, 0, 0, 0, time.UTC), true},
+		{time.Date(2025, 3, 16, 1, 45, 0, 0, time.UTC), true},
+		{time.Date(2025, 3, 16, 2, 0, 0, 0, time.UTC), false},
+	}
+	for _, testCase := range testCases {
+		if got := schedule.Active(testCase.time); got != testCase.active {
+			t.Errorf("Active(%v) = %v, expected %v", testCase.time, got, testCase.active)
+		}
+	}
+
+	for _, spec := range []string{"Mon 08:15-09:00", "Funday 08:00-09:00", "Mon 8-9", "Mon"} {
+		if _, err := ParseScheduleRanges(spec); !errors.Is(err, ErrInvalidSchedule) {
+			t.Errorf("Expected ErrInvalidSchedule for %q, got: %v", spec, err)
+		}
+	}
+}
+
+func TestNextTransition(t *testing.T) {
+	schedule, err := ParseScheduleRanges("Mon 08:00-18:00")
+	if err != nil {
+		t.Fatalf("Failed to parse ranges: %v", err)
+	}
+
+	next, ok := schedule.NextTransition(time.Date(2025, 3, 9, 12, 10, 0, 0, time.UTC))
+	if !ok || !next.Equal(time.Date(2025, 3, 10, 8, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected next transition: %v %v", next, ok)
+	}
+
+	next, ok = schedule.NextTransition(time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC))
+	if !ok || !next.Equal(time.Date(2025, 3, 10, 18, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected next transition: %v %v", next, ok)
+	}
+
+	var empty WeeklySchedule
+	if _, ok := empty.NextTransition(time.Now()); ok {
+		t.Errorf("Expected no transition for a constant schedule")
+	}
+}
@@ -0,0 +1,85 @@
+package scheduler
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Retention limits the frames kept in a directory. Zero values disable the
+// corresponding limit.
+type Retention struct {
+	MaxCount int
+	MaxAge   time.Duration
+	MaxBytes int64
+}
+
+// Apply removes the oldest frames of dir until all limits are met and
+// returns the removed paths. Subdirectories and hidden files are left alone.
+func (r Retention) Apply(dir string, now time.Time) ([]string, error) {
+	if r.MaxCount <= 0 && r.MaxAge <= 0 && r.MaxBytes <= 0 {
+		return nil, nil
+	}
+
+	frames, err := listFrames(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var total int64
+	for _, frame := range frames {
+		total += frame.Size()
+	}
+
+	var removed []string
+	for len(frames) > 0 {
+		oldest := frames[0]
+		expired := r.MaxAge > 0 && now.Sub(oldest.ModTime()) > r.MaxAge
+		tooMany := r.MaxCount > 0 && len(frames) > r.MaxCount
+		tooLarge := r.MaxBytes > 0 && total > r.MaxBytes
+		if !expired && !tooMany && !tooLarge {
+			break
+		}
+
+		// A frame removed by someone else in the meantime counts as removed
+		path := filepath.Join(dir, oldest.Name())
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return removed, err
+		}
+		removed = append(removed, path)
+		total -= oldest.Size()
+		frames = frames[1:]
+	}
+	return removed, nil
+}
+
+// listFrames returns the regular files of dir, oldest first
+func listFrames(dir string) ([]os.FileInfo, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var frames []os.FileInfo
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil, err
+		}
+		frames = append(frames, info)
+	}
+
+	sort.Slice(frames, func(i, j int) bool {
+		if !frames[i].ModTime().Equal(frames[j].ModTime()) {
+			return frames[i].ModTime().Before(frames[j].ModTime())
+		}
+		return frames[i].Name() < frames[j].Name()
+	})
+	return frames, nil
+}
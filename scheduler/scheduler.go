@@ -0,0 +1,190 @@
+// Package scheduler periodically captures camera snapshots to disk, enforces
+// retention on the captured frames and assembles them into timelapse videos.
+package scheduler
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"text/template"
+	"time"
+
+	sssg "github.com/RealKeyboardWarrior/synology-surveillance-station-go"
+)
+
+// DefaultNameTemplate stores the frames of every camera in their own
+// directory, named so that they sort chronologically
+const DefaultNameTemplate = `{{.Camera.ID}}/{{.Time.Format "20060102-150405"}}.jpg`
+
+// Snapshotter takes camera snapshots, as *sssg.SurveillanceStationClient does
+type Snapshotter interface {
+	TakeSnapshotWithOptionsContext(ctx context.Context, camera sssg.Camera, opts *sssg.SnapshotOptions) ([]byte, error)
+}
+
+// NameData is passed to the name template of every captured frame
+type NameData struct {
+	Camera sssg.Camera
+	Time   time.Time
+}
+
+// Scheduler captures snapshots of Cameras every Interval, while Schedule is
+// active, into Dir
+type Scheduler struct {
+	Client   Snapshotter
+	Cameras  []sssg.Camera
+	Interval time.Duration
+	// Schedule restricts captures to its active slots, in the same format as
+	// Camera.RecordSchedule. Nil captures around the clock.
+	Schedule *sssg.WeeklySchedule
+	Dir      string
+	// NameTemplate is a text/template for the frame path relative to Dir,
+	// executed with NameData. Defaults to DefaultNameTemplate.
+	NameTemplate string
+	// Retention is applied to every directory a frame is written to
+	Retention Retention
+	// Snapshot is passed on to every snapshot request
+	Snapshot *sssg.SnapshotOptions
+	// OnError is called for every failed capture or retention, it may be nil.
+	// Retention failures are reported with the first camera of the directory.
+	OnError func(camera sssg.Camera, err error)
+}
+
+// Run captures snapshots until ctx is done, starting right away
+func (s *Scheduler) Run(ctx context.Context) error {
+	if s.Client == nil || s.Interval <= 0 || s.Dir == "" {
+		return errors.New("scheduler needs a client, a positive interval and a directory")
+	}
+	tmpl, err := s.template()
+	if err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(s.Interval)
+	defer ticker.Stop()
+
+	now := time.Now()
+	for {
+		if s.Schedule == nil || s.Schedule.Active(now) {
+			s.capture(ctx, tmpl, now)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case now = <-ticker.C:
+		}
+	}
+}
+
+// CaptureOnce captures a single frame of every camera at now, regardless of
+// Schedule, and returns the paths written
+func (s *Scheduler) CaptureOnce(ctx context.Context, now time.Time) ([]string, error) {
+	tmpl, err := s.template()
+	if err != nil {
+		return nil, err
+	}
+	return s.capture(ctx, tmpl, now), nil
+}
+
+func (s *Scheduler) template() (*template.Template, error) {
+	text := s.NameTemplate
+	if text == "" {
+		text = DefaultNameTemplate
+	}
+	tmpl, err := template.New("name").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid name template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// capture snapshots all cameras concurrently, writes the frames and then
+// applies the retention to every directory written to, reporting failures
+// to OnError
+func (s *Scheduler) capture(ctx context.Context, tmpl *template.Template, now time.Time) []string {
+	var (
+		mu      sync.Mutex
+		written []string
+		dirs    = make(map[string]sssg.Camera)
+		order   []string
+		wg      sync.WaitGroup
+	)
+
+	// Bound every capture by the interval so a hung camera cannot pile up
+	captureCtx, cancel := context.WithTimeout(ctx, s.Interval)
+	defer cancel()
+
+	for _, camera := range s.Cameras {
+		wg.Add(1)
+		go func(camera sssg.Camera) {
+			defer wg.Done()
+			path, err := s.captureCamera(captureCtx, tmpl, camera, now)
+			if err != nil {
+				if s.OnError != nil {
+					s.OnError(camera, err)
+				}
+				return
+			}
+			mu.Lock()
+			written = append(written, path)
+			if _, ok := dirs[filepath.Dir(path)]; !ok {
+				dirs[filepath.Dir(path)] = camera
+				order = append(order, filepath.Dir(path))
+			}
+			mu.Unlock()
+		}(camera)
+	}
+	wg.Wait()
+
+	// Retention runs once per directory, as a name template may put the
+	// frames of several cameras into the same one
+	for _, dir := range order {
+		if _, err := s.Retention.Apply(dir, now); err != nil && s.OnError != nil {
+			s.OnError(dirs[dir], fmt.Errorf("failed to apply retention to %s: %w", dir, err))
+		}
+	}
+	return written
+}
+
+func (s *Scheduler) captureCamera(ctx context.Context, tmpl *template.Template, camera sssg.Camera, now time.Time) (string, error) {
+	var name bytes.Buffer
+	if err := tmpl.Execute(&name, NameData{Camera: camera, Time: now}); err != nil {
+		return "", fmt.Errorf("failed to render frame name: %w", err)
+	}
+	path := filepath.Join(s.Dir, name.String())
+
+	data, err := s.Client.TakeSnapshotWithOptionsContext(ctx, camera, s.Snapshot)
+	if err != nil {
+		return "", err
+	}
+	if err := writeFileAtomic(path, data); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// writeFileAtomic writes data to a temporary file next to path and renames
+// it into place, so readers never see a partially written frame
+func writeFileAtomic(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".frame-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,162 @@
+package scheduler
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	sssg "github.com/RealKeyboardWarrior/synology-surveillance-station-go"
+)
+
+// fakeSnapshotter returns the same JPEG for every camera
+type fakeSnapshotter struct {
+	data []byte
+}
+
+func (f *fakeSnapshotter) TakeSnapshotWithOptionsContext(ctx context.Context, camera sssg.Camera, opts *sssg.SnapshotOptions) ([]byte, error) {
+	return f.data, nil
+}
+
+func testJPEG(t *testing.T, width, height int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, image.NewGray(image.Rect(0, 0, width, height)), nil); err != nil {
+		t.Fatalf("Failed to encode JPEG: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestCaptureOnceWithRetention(t *testing.T) {
+	dir := t.TempDir()
+	s := &Scheduler{
+		Client:    &fakeSnapshotter{data: testJPEG(t, 8, 8)},
+		Cameras:   []sssg.Camera{{ID: 1}, {ID: 2}},
+		Interval:  time.Second,
+		Dir:       dir,
+		Retention: Retention{MaxCount: 2},
+	}
+
+	start := time.Date(2025, 3, 10, 8, 0, 0, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		now := start.Add(time.Duration(i) * time.Minute)
+		paths, err := s.CaptureOnce(context.Background(), now)
+		if err != nil {
+			t.Fatalf("Capture failed: %v", err)
+		}
+		if len(paths) != 2 {
+			t.Fatalf("Expected 2 frames, got %v", paths)
+		}
+		// Make the modification times follow the capture times
+		for _, path := range paths {
+			os.Chtimes(path, now, now)
+		}
+	}
+
+	frames, err := Frames(filepath.Join(dir, "1"))
+	if err != nil {
+		t.Fatalf("Failed to list frames: %v", err)
+	}
+	if len(frames) != 2 || !strings.HasSuffix(frames[0], "20250310-080100.jpg") {
+		t.Errorf("Expected the two newest frames to be kept, got %v", frames)
+	}
+}
+
+func TestRetentionMaxAgeAndBytes(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	for i, name := range []string{"a.jpg", "b.jpg", "c.jpg"} {
+		path := filepath.Join(dir, name)
+		os.WriteFile(path, make([]byte, 100), 0o644)
+		modTime := now.Add(-time.Duration(3-i) * time.Hour)
+		os.Chtimes(path, modTime, modTime)
+	}
+
+	removed, err := Retention{MaxAge: 150 * time.Minute}.Apply(dir, now)
+	if err != nil || len(removed) != 1 || filepath.Base(removed[0]) != "a.jpg" {
+		t.Errorf("Unexpected age based removal: %v %v", removed, err)
+	}
+
+	removed, err = Retention{MaxBytes: 150}.Apply(dir, now)
+	if err != nil || len(removed) != 1 || filepath.Base(removed[0]) != "b.jpg" {
+		t.Errorf("Unexpected size based removal: %v %v", removed, err)
+	}
+}
+
+func TestWriteTimelapse(t *testing.T) {
+	dir := t.TempDir()
+	var frames []string
+	for i := 0; i < 3; i++ {
+		path := filepath.Join(dir, fmt.Sprintf("frame-%d.jpg", i))
+		if err := os.WriteFile(path, testJPEG(t, 32, 16), 0o644); err != nil {
+			t.Fatalf("Failed to write frame: %v", err)
+		}
+		frames = append(frames, path)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteTimelapse(&buf, frames, 10); err != nil {
+		t.Fatalf("Failed to write timelapse: %v", err)
+	}
+	data := buf.Bytes()
+
+	if string(data[0:4]) != "RIFF" || string(data[8:12]) != "AVI " {
+		t.Fatalf("Missing RIFF AVI header")
+	}
+	if size := binary.LittleEndian.Uint32(data[4:8]); int(size) != len(data)-8 {
+		t.Errorf("RIFF size %d does not match file size %d", size, len(data)-8)
+	}
+	// The main header follows "RIFF....AVI LIST....hdrlavih...."
+	avih := data[32:]
+	if frameCount := binary.LittleEndian.Uint32(avih[16:20]); frameCount != 3 {
+		t.Errorf("Expected 3 frames, got %d", frameCount)
+	}
+	if width, height := binary.LittleEndian.Uint32(avih[32:36]), binary.LittleEndian.Uint32(avih[36:40]); width != 32 || height != 16 {
+		t.Errorf("Unexpected dimensions %dx%d", width, height)
+	}
+	if bytes.Count(data, []byte("00dc")) != 6 || !bytes.Contains(data, []byte("idx1")) {
+		t.Errorf("Expected 3 frame chunks and an index")
+	}
+}
+
+func TestCaptureOnceSharedDirectory(t *testing.T) {
+	dir := t.TempDir()
+	var mu sync.Mutex
+	var errs []error
+	s := &Scheduler{
+		Client:       &fakeSnapshotter{data: testJPEG(t, 8, 8)},
+		Cameras:      []sssg.Camera{{ID: 1}, {ID: 2}, {ID: 3}, {ID: 4}},
+		Interval:     time.Second,
+		Dir:          dir,
+		NameTemplate: `{{.Camera.ID}}-{{.Time.Format "20060102-150405"}}.jpg`,
+		Retention:    Retention{MaxCount: 4},
+		OnError: func(camera sssg.Camera, err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			errs = append(errs, err)
+		},
+	}
+
+	start := time.Date(2025, 3, 10, 8, 0, 0, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		if _, err := s.CaptureOnce(context.Background(), start.Add(time.Duration(i)*time.Minute)); err != nil {
+			t.Fatalf("Capture failed: %v", err)
+		}
+	}
+
+	if len(errs) != 0 {
+		t.Errorf("Unexpected errors: %v", errs)
+	}
+	frames, err := Frames(dir)
+	if err != nil || len(frames) != 4 {
+		t.Errorf("Expected 4 frames to be kept, got %v (%v)", frames, err)
+	}
+}
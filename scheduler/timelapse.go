@@ -0,0 +1,252 @@
+package scheduler
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/jpeg" // frames are JPEG encoded
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// AVI flags and fixed header sizes
+const (
+	avifHasIndex   = 0x10
+	aviifKeyframe  = 0x10
+	aviMainHdrSize = 56
+	aviStrHdrSize  = 56
+	bitmapHdrSize  = 40
+	idxEntrySize   = 16
+)
+
+// Frames returns the JPEG frames of dir sorted by name, which is
+// chronological for names produced by DefaultNameTemplate
+func Frames(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var frames []string
+	for _, entry := range entries {
+		ext := strings.ToLower(filepath.Ext(entry.Name()))
+		if entry.Type().IsRegular() && (ext == ".jpg" || ext == ".jpeg") {
+			frames = append(frames, filepath.Join(dir, entry.Name()))
+		}
+	}
+	sort.Strings(frames)
+	return frames, nil
+}
+
+// BuildTimelapse assembles the JPEG frames into an MJPEG AVI file at dst,
+// played back at fps frames per second
+func BuildTimelapse(dst string, frames []string, fps int) error {
+	f, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if err := WriteTimelapse(f, frames, fps); err != nil {
+		f.Close()
+		os.Remove(dst)
+		return err
+	}
+	return f.Close()
+}
+
+// WriteTimelapse writes the JPEG frames as an MJPEG AVI stream to w. Frames
+// are copied one at a time, so memory use does not grow with their number.
+// All frames should share the dimensions of the first one.
+func WriteTimelapse(w io.Writer, frames []string, fps int) error {
+	if len(frames) == 0 {
+		return errors.New("timelapse needs at least one frame")
+	}
+	if fps <= 0 {
+		return errors.New("timelapse needs a positive frame rate")
+	}
+
+	width, height, err := frameSize(frames[0])
+	if err != nil {
+		return err
+	}
+
+	// Sizes go into the headers up front, so collect them first
+	sizes := make([]uint32, len(frames))
+	var moviSize, maxSize uint32 = 4, 0
+	for i, frame := range frames {
+		info, err := os.Stat(frame)
+		if err != nil {
+			return err
+		}
+		sizes[i] = uint32(info.Size())
+		moviSize += 8 + padded(sizes[i])
+		if sizes[i] > maxSize {
+			maxSize = sizes[i]
+		}
+	}
+
+	const strlSize = 4 + 8 + aviStrHdrSize + 8 + bitmapHdrSize
+	const hdrlSize = 4 + 8 + aviMainHdrSize + 8 + strlSize
+	idxSize := uint32(idxEntrySize * len(frames))
+	riffSize := 4 + (8 + hdrlSize) + (8 + moviSize) + (8 + idxSize)
+
+	bw := bufio.NewWriter(w)
+	aw := &aviWriter{w: bw}
+
+	aw.fourCC("RIFF")
+	aw.u32(riffSize)
+	aw.fourCC("AVI ")
+
+	aw.fourCC("LIST")
+	aw.u32(hdrlSize)
+	aw.fourCC("hdrl")
+
+	aw.fourCC("avih")
+	aw.u32(aviMainHdrSize)
+	aw.u32(uint32(1000000 / fps)) // microseconds per frame
+	aw.u32(maxSize * uint32(fps)) // max bytes per second
+	aw.u32(0)                     // padding granularity
+	aw.u32(avifHasIndex)
+	aw.u32(uint32(len(frames)))
+	aw.u32(0) // initial frames
+	aw.u32(1) // streams
+	aw.u32(padded(maxSize))
+	aw.u32(uint32(width))
+	aw.u32(uint32(height))
+	aw.u32(0)
+	aw.u32(0)
+	aw.u32(0)
+	aw.u32(0)
+
+	aw.fourCC("LIST")
+	aw.u32(strlSize)
+	aw.fourCC("strl")
+
+	aw.fourCC("strh")
+	aw.u32(aviStrHdrSize)
+	aw.fourCC("vids")
+	aw.fourCC("MJPG")
+	aw.u32(0) // flags
+	aw.u32(0) // priority and language
+	aw.u32(0) // initial frames
+	aw.u32(1) // scale
+	aw.u32(uint32(fps))
+	aw.u32(0) // start
+	aw.u32(uint32(len(frames)))
+	aw.u32(padded(maxSize))
+	aw.u32(0xffffffff) // default quality
+	aw.u32(0)          // sample size
+	aw.u16(0)
+	aw.u16(0)
+	aw.u16(uint16(width))
+	aw.u16(uint16(height))
+
+	aw.fourCC("strf")
+	aw.u32(bitmapHdrSize)
+	aw.u32(bitmapHdrSize)
+	aw.u32(uint32(width))
+	aw.u32(uint32(height))
+	aw.u16(1)  // planes
+	aw.u16(24) // bits per pixel
+	aw.fourCC("MJPG")
+	aw.u32(uint32(width * height * 3))
+	aw.u32(0)
+	aw.u32(0)
+	aw.u32(0)
+	aw.u32(0)
+
+	aw.fourCC("LIST")
+	aw.u32(moviSize)
+	aw.fourCC("movi")
+	for i, frame := range frames {
+		aw.fourCC("00dc")
+		aw.u32(sizes[i])
+		if aw.err == nil {
+			aw.err = copyFrame(bw, frame, sizes[i])
+		}
+		if sizes[i]%2 == 1 {
+			aw.bytes([]byte{0})
+		}
+	}
+
+	aw.fourCC("idx1")
+	aw.u32(idxSize)
+	offset := uint32(4)
+	for _, size := range sizes {
+		aw.fourCC("00dc")
+		aw.u32(aviifKeyframe)
+		aw.u32(offset)
+		aw.u32(size)
+		offset += 8 + padded(size)
+	}
+
+	if aw.err != nil {
+		return fmt.Errorf("failed to write timelapse: %w", aw.err)
+	}
+	return bw.Flush()
+}
+
+// frameSize returns the dimensions of a JPEG frame
+func frameSize(path string) (int, int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer f.Close()
+
+	config, _, err := image.DecodeConfig(f)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to decode frame %s: %w", path, err)
+	}
+	return config.Width, config.Height, nil
+}
+
+// copyFrame copies exactly size bytes of the frame file into w, failing if
+// the file changed since its size was taken
+func copyFrame(w io.Writer, path string, size uint32) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = io.CopyN(w, f, int64(size))
+	return err
+}
+
+// padded rounds size up to the even chunk boundary RIFF requires
+func padded(size uint32) uint32 {
+	return size + size%2
+}
+
+// aviWriter writes little-endian RIFF fields, remembering the first error
+type aviWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (a *aviWriter) bytes(b []byte) {
+	if a.err == nil {
+		_, a.err = a.w.Write(b)
+	}
+}
+
+func (a *aviWriter) fourCC(code string) {
+	a.bytes([]byte(code))
+}
+
+func (a *aviWriter) u32(v uint32) {
+	var b [4]byte
+	binary.LittleEndian.PutUint32(b[:], v)
+	a.bytes(b[:])
+}
+
+func (a *aviWriter) u16(v uint16) {
+	var b [2]byte
+	binary.LittleEndian.PutUint16(b[:], v)
+	a.bytes(b[:])
+}
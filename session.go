@@ -0,0 +1,162 @@
+package sssg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+var _ io.Closer = (*SurveillanceStationClient)(nil)
+
+// Logout ends the current session on the Surveillance Station
+func (c *SurveillanceStationClient) Logout() error {
+	return c.LogoutContext(context.Background())
+}
+
+// LogoutContext ends the current session on the Surveillance Station, bound to ctx
+func (c *SurveillanceStationClient) LogoutContext(ctx context.Context) error {
+	sid := c.sessionID()
+	if sid == "" {
+		return nil
+	}
+	if err := c.logoutSession(ctx, sid); err != nil {
+		return err
+	}
+
+	// Only forget the session if nobody logged in again in the meantime
+	c.mu.Lock()
+	if c.Session == sid {
+		c.Session = ""
+		c.loginTime = time.Time{}
+	}
+	c.mu.Unlock()
+	return nil
+}
+
+// logoutSession ends the session identified by sid
+func (c *SurveillanceStationClient) logoutSession(ctx context.Context, sid string) error {
+	endpoint, api, params, err := c.authRequest(ctx, "Logout")
+	if err != nil {
+		return fmt.Errorf("logout failed: %w", err)
+	}
+	params.Set("_sid", sid)
+
+	if err := c.do(ctx, endpoint, api, "Logout", params, nil); err != nil {
+		return fmt.Errorf("logout failed: %w", err)
+	}
+	return nil
+}
+
+// Close stops the keep-alive, if any, and logs out of the current session
+func (c *SurveillanceStationClient) Close() error {
+	c.StopKeepAlive()
+	return c.Logout()
+}
+
+// SessionAge returns how long ago the current session was established, or
+// zero when the client is not logged in
+func (c *SurveillanceStationClient) SessionAge() time.Duration {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.Session == "" || c.loginTime.IsZero() {
+		return 0
+	}
+	return time.Since(c.loginTime)
+}
+
+// StartKeepAlive touches the session every interval so it never reaches
+// DSM's idle timeout; pick an interval shorter than that timeout. The
+// session id stays the same unless the NAS expired it anyway, in which case
+// the client logs in again. Errors
+// encountered while refreshing are passed to onError, which may be nil.
+// Calling StartKeepAlive again replaces the previous keep-alive. It fails
+// without starting anything when interval is not positive.
+func (c *SurveillanceStationClient) StartKeepAlive(interval time.Duration, onError func(error)) error {
+	if interval <= 0 {
+		return fmt.Errorf("keep-alive interval must be positive, got %s", interval)
+	}
+	c.StopKeepAlive()
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	ctx, cancel := context.WithCancel(context.Background())
+	c.mu.Lock()
+	c.keepAliveStop = stop
+	c.keepAliveCancel = cancel
+	c.keepAliveDone = done
+	c.mu.Unlock()
+
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				if c.SessionAge() < interval {
+					// Someone else logged in recently, no need to refresh yet
+					continue
+				}
+				if err := c.refreshSession(ctx); err != nil && onError != nil {
+					onError(err)
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+// StopKeepAlive stops a keep-alive started by StartKeepAlive, aborting a
+// refresh in flight, and waits for it to exit. It is a no-op when no
+// keep-alive is running.
+func (c *SurveillanceStationClient) StopKeepAlive() {
+	c.mu.Lock()
+	stop, cancel, done := c.keepAliveStop, c.keepAliveCancel, c.keepAliveDone
+	c.keepAliveStop, c.keepAliveCancel, c.keepAliveDone = nil, nil, nil
+	c.mu.Unlock()
+
+	if stop != nil {
+		close(stop)
+		cancel()
+		<-done
+	}
+}
+
+// refreshSession keeps the current session alive with a cheap authenticated
+// call, so URLs and streams bound to its id keep working. Only when the NAS
+// reports the session as expired does it log in again.
+func (c *SurveillanceStationClient) refreshSession(ctx context.Context) error {
+	if err := c.callAPI(ctx, "SYNO.SurveillanceStation.Info", "GetInfo", 1, 8, nil, nil); err != nil {
+		return fmt.Errorf("failed to keep session alive: %w", err)
+	}
+	return nil
+}
+
+// authRequest returns the endpoint, API name and base parameters for method
+// of the authentication API. SYNO.API.Auth is preferred when the NAS
+// advertises it, otherwise the Surveillance Station ThirdParty endpoint is used.
+func (c *SurveillanceStationClient) authRequest(ctx context.Context, method string) (string, string, url.Values, error) {
+	params := url.Values{}
+	endpoint, version, err := c.resolveAPI(ctx, "SYNO.API.Auth", 3, 6)
+	if errors.Is(err, ErrAPIUnsupported) {
+		endpoint = fmt.Sprintf("%s/webapi/SurveillanceStation/ThirdParty/Auth/%s/v1", c.BaseURL, method)
+		return endpoint, "SYNO.SurveillanceStation.ThirdParty.Auth", params, nil
+	}
+	if err != nil {
+		return "", "", nil, err
+	}
+
+	params.Set("api", "SYNO.API.Auth")
+	params.Set("method", method)
+	params.Set("version", strconv.Itoa(version))
+	params.Set("session", "SurveillanceStation")
+	params.Set("format", "sid")
+	return endpoint, "SYNO.API.Auth", params, nil
+}
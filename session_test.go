@@ -0,0 +1,161 @@
+package sssg
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeAuthServer hands out numbered session ids, records logouts and
+// answers GetInfo calls, failing them for the sids in expired
+type fakeAuthServer struct {
+	mu        sync.Mutex
+	logins    int
+	loggedOut []string
+	pinged    []string
+	expired   map[string]bool
+}
+
+func (f *fakeAuthServer) handle(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch {
+	case strings.HasSuffix(r.URL.Path, "/Login/v1"):
+		f.logins++
+		fmt.Fprintf(w, `{"success":true,"data":{"sid":"sid-%d"}}`, f.logins)
+	case strings.HasSuffix(r.URL.Path, "/Logout/v1"):
+		f.loggedOut = append(f.loggedOut, r.URL.Query().Get("_sid"))
+		w.Write([]byte(`{"success":true}`))
+	case r.URL.Query().Get("method") == "GetInfo":
+		sid := r.URL.Query().Get("_sid")
+		f.pinged = append(f.pinged, sid)
+		if f.expired[sid] {
+			w.Write([]byte(`{"success":false,"error":{"code":119}}`))
+			return
+		}
+		w.Write([]byte(`{"success":true,"data":{}}`))
+	default:
+		w.Write([]byte(`{"success":false,"error":{"code":103}}`))
+	}
+}
+
+func TestLogoutAndClose(t *testing.T) {
+	auth := &fakeAuthServer{}
+	client := newTestClient(t, auth.handle)
+
+	if err := client.Login(); err != nil {
+		t.Fatalf("Login failed: %v", err)
+	}
+	if client.SessionAge() <= 0 {
+		t.Errorf("Expected a positive session age after login")
+	}
+
+	if err := client.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if client.Session != "" || client.SessionAge() != 0 {
+		t.Errorf("Expected session to be cleared, got %q", client.Session)
+	}
+	if len(auth.loggedOut) != 1 || auth.loggedOut[0] != "sid-1" {
+		t.Errorf("Unexpected logouts: %v", auth.loggedOut)
+	}
+
+	// Logging out without a session is a no-op
+	if err := client.Logout(); err != nil {
+		t.Errorf("Second logout failed: %v", err)
+	}
+}
+
+func TestKeepAliveKeepsSession(t *testing.T) {
+	auth := &fakeAuthServer{}
+	client := newTestClient(t, auth.handle)
+
+	if err := client.Login(); err != nil {
+		t.Fatalf("Login failed: %v", err)
+	}
+
+	if err := client.StartKeepAlive(20*time.Millisecond, func(err error) {
+		t.Errorf("Keep-alive failed: %v", err)
+	}); err != nil {
+		t.Fatalf("Failed to start keep-alive: %v", err)
+	}
+	time.Sleep(70 * time.Millisecond)
+	client.StopKeepAlive()
+
+	if got := client.sessionID(); got != "sid-1" {
+		t.Errorf("Expected the session to be kept, got %s", got)
+	}
+
+	auth.mu.Lock()
+	defer auth.mu.Unlock()
+	if len(auth.pinged) == 0 || auth.pinged[0] != "sid-1" {
+		t.Errorf("Expected the session to be pinged, got %v", auth.pinged)
+	}
+	if auth.logins != 1 || len(auth.loggedOut) != 0 {
+		t.Errorf("Expected no re-login, got %d logins and logouts %v", auth.logins, auth.loggedOut)
+	}
+}
+
+func TestKeepAliveRenewsExpiredSession(t *testing.T) {
+	auth := &fakeAuthServer{expired: map[string]bool{"sid-1": true}}
+	client := newTestClient(t, auth.handle)
+
+	if err := client.Login(); err != nil {
+		t.Fatalf("Login failed: %v", err)
+	}
+
+	if err := client.StartKeepAlive(20*time.Millisecond, func(err error) {
+		t.Errorf("Keep-alive failed: %v", err)
+	}); err != nil {
+		t.Fatalf("Failed to start keep-alive: %v", err)
+	}
+	time.Sleep(70 * time.Millisecond)
+	client.StopKeepAlive()
+
+	if got := client.sessionID(); got != "sid-2" {
+		t.Errorf("Expected the expired session to be replaced once, got %s", got)
+	}
+}
+
+func TestStopKeepAliveAbortsHangingRefresh(t *testing.T) {
+	entered := make(chan struct{}, 1)
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case entered <- struct{}{}:
+		default:
+		}
+		<-r.Context().Done()
+	})
+	client.setSession("sid-1")
+
+	if err := client.StartKeepAlive(time.Millisecond, nil); err != nil {
+		t.Fatalf("Failed to start keep-alive: %v", err)
+	}
+	<-entered
+
+	stopped := make(chan struct{})
+	go func() {
+		client.StopKeepAlive()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("StopKeepAlive blocked on a hanging refresh")
+	}
+}
+
+func TestStartKeepAliveRejectsInterval(t *testing.T) {
+	client := NewClient("http://nas", "user", "pass", false)
+	for _, interval := range []time.Duration{0, -time.Second} {
+		if err := client.StartKeepAlive(interval, nil); err == nil {
+			t.Errorf("Expected an error for interval %s", interval)
+		}
+	}
+	// Nothing was started, so stopping must not block
+	client.StopKeepAlive()
+}
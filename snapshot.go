@@ -0,0 +1,231 @@
+package sssg
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/jpeg" // snapshots are JPEG encoded
+	"io"
+	"mime"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrNotAnImage is returned when a snapshot response does not contain an image
+var ErrNotAnImage = errors.New("response is not an image")
+
+// ProfileType selects one of the stream profiles of a camera
+type ProfileType int
+
+const (
+	ProfileHigh   ProfileType = 0
+	ProfileMedium ProfileType = 1
+	ProfileLow    ProfileType = 2
+)
+
+func (p ProfileType) String() string {
+	switch p {
+	case ProfileHigh:
+		return "High"
+	case ProfileMedium:
+		return "Medium"
+	case ProfileLow:
+		return "Low"
+	}
+	return fmt.Sprintf("ProfileType(%d)", int(p))
+}
+
+// SnapshotOptions controls how a snapshot is taken
+type SnapshotOptions struct {
+	// ProfileType selects the stream profile to take the snapshot from,
+	// ProfileHigh by default
+	ProfileType ProfileType
+}
+
+// TakeSnapshotWithOptions returns the camera snapshot as bytes
+func (c *SurveillanceStationClient) TakeSnapshotWithOptions(camera Camera, opts *SnapshotOptions) ([]byte, error) {
+	return c.TakeSnapshotWithOptionsContext(context.Background(), camera, opts)
+}
+
+// TakeSnapshotWithOptionsContext returns the camera snapshot as bytes, bound to ctx
+func (c *SurveillanceStationClient) TakeSnapshotWithOptionsContext(ctx context.Context, camera Camera, opts *SnapshotOptions) ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := c.WriteSnapshotContext(ctx, camera, &buf, opts); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// SnapshotImage returns the decoded camera snapshot
+func (c *SurveillanceStationClient) SnapshotImage(camera Camera, opts *SnapshotOptions) (image.Image, error) {
+	return c.SnapshotImageContext(context.Background(), camera, opts)
+}
+
+// SnapshotImageContext returns the decoded camera snapshot, bound to ctx
+func (c *SurveillanceStationClient) SnapshotImageContext(ctx context.Context, camera Camera, opts *SnapshotOptions) (image.Image, error) {
+	data, err := c.TakeSnapshotWithOptionsContext(ctx, camera, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode snapshot for camera ID %d: %w", camera.ID, err)
+	}
+	return img, nil
+}
+
+// WriteSnapshot streams the camera snapshot into w without buffering it and
+// returns the number of bytes written
+func (c *SurveillanceStationClient) WriteSnapshot(camera Camera, w io.Writer, opts *SnapshotOptions) (int64, error) {
+	return c.WriteSnapshotContext(context.Background(), camera, w, opts)
+}
+
+// WriteSnapshotContext streams the camera snapshot into w, bound to ctx
+func (c *SurveillanceStationClient) WriteSnapshotContext(ctx context.Context, camera Camera, w io.Writer, opts *SnapshotOptions) (int64, error) {
+	if opts == nil {
+		opts = &SnapshotOptions{}
+	}
+	params := url.Values{}
+	params.Set("id", strconv.Itoa(camera.ID))
+	params.Set("profileType", strconv.Itoa(int(opts.ProfileType)))
+
+	endpoint, params, err := c.prepareAPI(ctx, "SYNO.SurveillanceStation.Camera", "GetSnapshot", 1, 9, params)
+	if err != nil {
+		return 0, fmt.Errorf("failed to take snapshot for camera ID %d: %w", camera.ID, err)
+	}
+
+	var written int64
+	err = c.withSession(ctx, func(sid string) error {
+		params.Set("_sid", sid)
+		n, err := c.writeImage(ctx, endpoint, "SYNO.SurveillanceStation.Camera", "GetSnapshot", params, w)
+		written = n
+		return err
+	})
+	if err != nil {
+		return written, fmt.Errorf("failed to take snapshot for camera ID %d: %w", camera.ID, err)
+	}
+	return written, nil
+}
+
+// writeImage requests an image from endpoint and copies it into w. JSON
+// error envelopes are decoded into an *APIError, and any other non-image
+// response fails with ErrNotAnImage before anything is written.
+func (c *SurveillanceStationClient) writeImage(ctx context.Context, endpoint, api, method string, params url.Values, w io.Writer) (int64, error) {
+	resp, err := c.get(ctx, endpoint, params)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if err := checkImageResponse(resp.Header.Get("Content-Type"), resp.Body, api, method); err != nil {
+		return 0, err
+	}
+
+	written, err := io.Copy(w, resp.Body)
+	if err != nil {
+		return written, fmt.Errorf("failed to read image data: %w", err)
+	}
+	return written, nil
+}
+
+// checkImageResponse verifies that a response of the given content type is
+// an image, decoding body into an error otherwise
+func checkImageResponse(contentType string, body io.Reader, api, method string) error {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch {
+	case strings.HasPrefix(mediaType, "image/"):
+		return nil
+	case mediaType == "application/json" || mediaType == "text/plain":
+		// Surveillance Station reports errors as JSON, sometimes labelled as text
+		if err := decodeResponse(body, api, method, nil); err != nil {
+			var apiErr *APIError
+			if errors.As(err, &apiErr) {
+				return err
+			}
+		}
+	}
+	return fmt.Errorf("%w: got content type %q", ErrNotAnImage, contentType)
+}
+
+// TakeSnapshotsOptions controls how TakeSnapshots and StreamSnapshots fan
+// out over cameras
+type TakeSnapshotsOptions struct {
+	// Concurrency is the maximum number of snapshots in flight, 4 by default
+	Concurrency int
+	// Timeout bounds every single snapshot, zero means no timeout
+	Timeout time.Duration
+	// Snapshot is passed on to every snapshot request
+	Snapshot *SnapshotOptions
+}
+
+// SnapshotResult is the outcome of a snapshot of a single camera
+type SnapshotResult struct {
+	Camera Camera
+	Data   []byte
+	Err    error
+}
+
+// TakeSnapshots takes a snapshot of every camera concurrently and returns
+// the results keyed by camera ID once all of them are done
+func (c *SurveillanceStationClient) TakeSnapshots(ctx context.Context, cameras []Camera, opts *TakeSnapshotsOptions) map[int]SnapshotResult {
+	results := make(map[int]SnapshotResult, len(cameras))
+	for result := range c.StreamSnapshots(ctx, cameras, opts) {
+		results[result.Camera.ID] = result
+	}
+	return results
+}
+
+// StreamSnapshots takes a snapshot of every camera concurrently and delivers
+// each result as soon as it is available, so one slow camera does not delay
+// the others. The channel is closed after the last result. Cameras that had
+// not been started when ctx is done report the context error.
+func (c *SurveillanceStationClient) StreamSnapshots(ctx context.Context, cameras []Camera, opts *TakeSnapshotsOptions) <-chan SnapshotResult {
+	if opts == nil {
+		opts = &TakeSnapshotsOptions{}
+	}
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = 4
+	}
+
+	// Buffered so workers never block on a consumer that stopped reading
+	results := make(chan SnapshotResult, len(cameras))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	go func() {
+		defer close(results)
+		for _, camera := range cameras {
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				results <- SnapshotResult{Camera: camera, Err: ctx.Err()}
+				continue
+			}
+
+			wg.Add(1)
+			go func(camera Camera) {
+				defer wg.Done()
+				defer func() { <-sem }()
+
+				snapshotCtx := ctx
+				if opts.Timeout > 0 {
+					var cancel context.CancelFunc
+					snapshotCtx, cancel = context.WithTimeout(ctx, opts.Timeout)
+					defer cancel()
+				}
+				data, err := c.TakeSnapshotWithOptionsContext(snapshotCtx, camera, opts.Snapshot)
+				results <- SnapshotResult{Camera: camera, Data: data, Err: err}
+			}(camera)
+		}
+		wg.Wait()
+	}()
+
+	return results
+}
@@ -0,0 +1,136 @@
+package sssg
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/jpeg"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testJPEG returns a small encoded JPEG image
+func testJPEG(t *testing.T) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, image.NewGray(image.Rect(0, 0, 16, 8)), nil); err != nil {
+		t.Fatalf("Failed to encode JPEG: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestSnapshotImage(t *testing.T) {
+	jpegData := testJPEG(t)
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("profileType"); got != "2" {
+			t.Errorf("Unexpected profile type: %s", got)
+		}
+		w.Header().Set("Content-Type", "image/jpeg")
+		w.Write(jpegData)
+	})
+
+	img, err := client.SnapshotImage(Camera{ID: 1}, &SnapshotOptions{ProfileType: ProfileLow})
+	if err != nil {
+		t.Fatalf("Failed to take snapshot: %v", err)
+	}
+	if bounds := img.Bounds(); bounds.Dx() != 16 || bounds.Dy() != 8 {
+		t.Errorf("Unexpected image bounds: %v", bounds)
+	}
+}
+
+func TestSnapshotErrorResponses(t *testing.T) {
+	testCases := []struct {
+		name        string
+		contentType string
+		body        string
+		check       func(error) bool
+	}{
+		{
+			name:        "JSON error envelope",
+			contentType: "application/json; charset=utf-8",
+			body:        `{"success":false,"error":{"code":402}}`,
+			check: func(err error) bool {
+				var apiErr *APIError
+				return errors.As(err, &apiErr) && apiErr.Code == 402
+			},
+		},
+		{
+			name:        "HTML error page",
+			contentType: "text/html",
+			body:        "<html>Not Found</html>",
+			check:       func(err error) bool { return errors.Is(err, ErrNotAnImage) },
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", testCase.contentType)
+				w.Write([]byte(testCase.body))
+			})
+
+			var buf bytes.Buffer
+			_, err := client.WriteSnapshot(Camera{ID: 1}, &buf, nil)
+			if !testCase.check(err) {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			if buf.Len() != 0 {
+				t.Errorf("Expected nothing to be written, got %d bytes", buf.Len())
+			}
+		})
+	}
+}
+
+func TestTakeSnapshots(t *testing.T) {
+	jpegData := testJPEG(t)
+	var inFlight, maxInFlight int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		current := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			seen := atomic.LoadInt32(&maxInFlight)
+			if current <= seen || atomic.CompareAndSwapInt32(&maxInFlight, seen, current) {
+				break
+			}
+		}
+
+		if r.URL.Query().Get("id") == "3" {
+			// A hung camera must not hold up the others
+			select {
+			case <-r.Context().Done():
+			case <-time.After(5 * time.Second):
+			}
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+		w.Header().Set("Content-Type", "image/jpeg")
+		w.Write(jpegData)
+	})
+
+	cameras := []Camera{{ID: 1}, {ID: 2}, {ID: 3}, {ID: 4}, {ID: 5}}
+	results := client.TakeSnapshots(context.Background(), cameras, &TakeSnapshotsOptions{
+		Concurrency: 2,
+		Timeout:     100 * time.Millisecond,
+	})
+
+	if len(results) != len(cameras) {
+		t.Fatalf("Expected %d results, got %d", len(cameras), len(results))
+	}
+	for id, result := range results {
+		if id == 3 {
+			if !errors.Is(result.Err, context.DeadlineExceeded) {
+				t.Errorf("Expected camera 3 to time out, got: %v", result.Err)
+			}
+			continue
+		}
+		if result.Err != nil || !bytes.Equal(result.Data, jpegData) {
+			t.Errorf("Unexpected result for camera %d: %v", id, result.Err)
+		}
+	}
+	if got := atomic.LoadInt32(&maxInFlight); got > 2 {
+		t.Errorf("Expected at most 2 snapshots in flight, got %d", got)
+	}
+}
@@ -0,0 +1,49 @@
+package sssg
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TOTP parameters used by DSM's 2-step verification (RFC 6238 defaults)
+const (
+	totpPeriod = 30
+	totpDigits = 6
+)
+
+// GenerateTOTP returns the time-based one-time code for the base32 encoded
+// secret at time t, as shown by an authenticator app for the same secret
+func GenerateTOTP(secret string, t time.Time) (string, error) {
+	normalized := strings.ToUpper(strings.ReplaceAll(secret, " ", ""))
+	normalized = strings.TrimRight(normalized, "=")
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(normalized)
+	if err != nil {
+		return "", fmt.Errorf("invalid TOTP secret: %w", err)
+	}
+
+	return hotp(key, uint64(t.Unix()/totpPeriod), totpDigits), nil
+}
+
+// hotp implements the HMAC-based one-time password algorithm of RFC 4226
+func hotp(key []byte, counter uint64, digits int) string {
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], counter)
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	code := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	mod := uint32(1)
+	for i := 0; i < digits; i++ {
+		mod *= 10
+	}
+	return fmt.Sprintf("%0*d", digits, code%mod)
+}
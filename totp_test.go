@@ -0,0 +1,38 @@
+package sssg
+
+import (
+	"encoding/base32"
+	"testing"
+	"time"
+)
+
+func TestGenerateTOTP(t *testing.T) {
+	// RFC 6238 appendix B test vectors for SHA1, truncated to 6 digits
+	secret := base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+	testCases := []struct {
+		unix int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+	}
+
+	for _, testCase := range testCases {
+		code, err := GenerateTOTP(secret, time.Unix(testCase.unix, 0))
+		if err != nil {
+			t.Fatalf("Failed to generate TOTP: %v", err)
+		}
+		if code != testCase.code {
+			t.Errorf("TOTP at %d = %s, expected %s", testCase.unix, code, testCase.code)
+		}
+	}
+}
+
+func TestGenerateTOTPInvalidSecret(t *testing.T) {
+	if _, err := GenerateTOTP("not base32!", time.Now()); err == nil {
+		t.Errorf("Expected an error for an invalid secret")
+	}
+}